@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var channelCreatePrivate bool
+
+func init() {
+	channelCreateCmd.Flags().BoolVarP(&channelCreatePrivate, "private", "p", false, "Create a private channel")
+
+	channelCmd.AddCommand(channelCreateCmd)
+	channelCmd.AddCommand(channelJoinCmd)
+	channelCmd.AddCommand(channelLeaveCmd)
+	channelCmd.AddCommand(channelArchiveCmd)
+	channelCmd.AddCommand(channelUnarchiveCmd)
+	channelCmd.AddCommand(channelRenameCmd)
+	channelCmd.AddCommand(channelInviteCmd)
+	channelCmd.AddCommand(channelKickCmd)
+	channelCmd.AddCommand(channelTopicCmd)
+	channelCmd.AddCommand(channelPurposeCmd)
+	channelCmd.AddCommand(channelInfoCmd)
+	channelCmd.AddCommand(channelMembersCmd)
+	rootCmd.AddCommand(channelCmd)
+}
+
+type channelMember struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Print a channel returned by the api as json when --json is set
+func printChannelJSON(channel *slack.Channel) error {
+	if !jsonOutput || channel == nil {
+		return nil
+	}
+	return printJSON(channel)
+}
+
+// Resolve every user argument to a user id
+func resolveUserArgs(args []string) ([]string, error) {
+	users := make([]string, 0, len(args))
+	for _, arg := range args {
+		id, err := slackutils.ResolveUserID(arg)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve %s: %w", arg, err)
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+var channelCmd = &cobra.Command{
+	Use:   "channel",
+	Short: "Manage channels",
+}
+
+var channelCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a channel and print its id",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel, err := config.SlackClient.CreateConversation(slack.CreateConversationParams{
+			ChannelName: args[0],
+			IsPrivate:   channelCreatePrivate,
+		})
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(channel)
+		}
+
+		fmt.Println(channel.ID)
+		return nil
+	},
+}
+
+var channelJoinCmd = &cobra.Command{
+	Use:   "join <channel>",
+	Short: "Join a channel",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		channel, _, _, err := config.SlackClient.JoinConversation(channelID)
+		if err != nil {
+			return err
+		}
+
+		return printChannelJSON(channel)
+	},
+}
+
+var channelLeaveCmd = &cobra.Command{
+	Use:   "leave <channel>",
+	Short: "Leave a channel",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		_, err = config.SlackClient.LeaveConversation(channelID)
+		return err
+	},
+}
+
+var channelArchiveCmd = &cobra.Command{
+	Use:   "archive <channel>",
+	Short: "Archive a channel",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		return config.SlackClient.ArchiveConversation(channelID)
+	},
+}
+
+var channelUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <channel>",
+	Short: "Unarchive a channel",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		return config.SlackClient.UnArchiveConversation(channelID)
+	},
+}
+
+var channelRenameCmd = &cobra.Command{
+	Use:   "rename <channel> <name>",
+	Short: "Rename a channel",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		channel, err := config.SlackClient.RenameConversation(channelID, args[1])
+		if err != nil {
+			return err
+		}
+
+		return printChannelJSON(channel)
+	},
+}
+
+var channelInviteCmd = &cobra.Command{
+	Use:   "invite <channel> <@user>...",
+	Short: "Invite users to a channel",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		users, err := resolveUserArgs(args[1:])
+		if err != nil {
+			return err
+		}
+
+		channel, err := config.SlackClient.InviteUsersToConversation(channelID, users...)
+		if err != nil {
+			return err
+		}
+
+		return printChannelJSON(channel)
+	},
+}
+
+var channelKickCmd = &cobra.Command{
+	Use:   "kick <channel> <@user>...",
+	Short: "Remove users from a channel",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		users, err := resolveUserArgs(args[1:])
+		if err != nil {
+			return err
+		}
+
+		for i, user := range users {
+			if err := config.SlackClient.KickUserFromConversation(channelID, user); err != nil {
+				return fmt.Errorf("could not remove %s: %w", args[i+1], err)
+			}
+		}
+
+		return nil
+	},
+}
+
+var channelTopicCmd = &cobra.Command{
+	Use:   "topic <channel> [topic]",
+	Short: "Show or set the topic of a channel",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		var channel *slack.Channel
+		if len(args) == 2 {
+			channel, err = config.SlackClient.SetTopicOfConversation(channelID, args[1])
+		} else {
+			channel, err = config.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: channelID})
+		}
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(channel.Topic)
+		}
+
+		if len(args) == 1 {
+			fmt.Println(channel.Topic.Value)
+		}
+		return nil
+	},
+}
+
+var channelPurposeCmd = &cobra.Command{
+	Use:   "purpose <channel> [purpose]",
+	Short: "Show or set the purpose of a channel",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		var channel *slack.Channel
+		if len(args) == 2 {
+			channel, err = config.SlackClient.SetPurposeOfConversation(channelID, args[1])
+		} else {
+			channel, err = config.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: channelID})
+		}
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(channel.Purpose)
+		}
+
+		if len(args) == 1 {
+			fmt.Println(channel.Purpose.Value)
+		}
+		return nil
+	},
+}
+
+var channelInfoCmd = &cobra.Command{
+	Use:   "info <channel>",
+	Short: "Show details about a channel",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		channel, err := config.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{
+			ChannelID:         channelID,
+			IncludeNumMembers: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(channel)
+		}
+
+		users := slackutils.NewUserCache()
+		creator, err := users.Resolve(channel.Creator)
+		if err != nil {
+			creator = channel.Creator
+		}
+
+		fmt.Printf("Name:     #%s\n", channel.Name)
+		fmt.Printf("ID:       %s\n", channel.ID)
+		fmt.Printf("Private:  %t\n", channel.IsPrivate)
+		fmt.Printf("Archived: %t\n", channel.IsArchived)
+		fmt.Printf("Members:  %d\n", channel.NumMembers)
+		fmt.Printf("Created:  %s by @%s\n", channel.Created.Time().In(slackLocation()).Format("Jan 2 2006"), creator)
+		if channel.Topic.Value != "" {
+			fmt.Printf("Topic:    %s\n", channel.Topic.Value)
+		}
+		if channel.Purpose.Value != "" {
+			fmt.Printf("Purpose:  %s\n", channel.Purpose.Value)
+		}
+
+		return nil
+	},
+}
+
+var channelMembersCmd = &cobra.Command{
+	Use:   "members <channel>",
+	Short: "List the members of a channel",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveChannelID(args[0])
+		if err != nil {
+			return err
+		}
+
+		ids, err := slackutils.GetAllConversationMembers(channelID)
+		if err != nil {
+			return err
+		}
+
+		// Members from other workspaces are missing from the user list and are looked up one by one
+		users := slackutils.NewUserCache()
+		if err := users.Load(); err != nil {
+			return err
+		}
+
+		members := make([]channelMember, len(ids))
+		for i, id := range ids {
+			name, err := users.Resolve(id)
+			if err != nil {
+				name = id
+			}
+			members[i] = channelMember{ID: id, Name: name}
+		}
+
+		if jsonOutput {
+			return printJSON(members)
+		}
+
+		for _, m := range members {
+			fmt.Printf("%s\t@%s\n", m.ID, m.Name)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+var cleanupDryRun bool
+var cleanupYes bool
+var cleanupLogFile string
+
+func init() {
+	home, _ := os.UserHomeDir()
+
+	cleanupCmd.PersistentFlags().StringVar(&cleanupLogFile, "log", path.Join(home, ".config/slackcli-cleanup-log.json"), "File recording every cleanup action so it can be undone")
+	cleanupCmd.PersistentFlags().BoolVarP(&cleanupYes, "yes", "y", false, "Skip the confirmation prompt")
+	cleanupCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "Print the channels the policies match without changing anything")
+
+	cleanupCmd.AddCommand(cleanupUndoCmd)
+	cleanupCmd.AddCommand(cleanupLogCmd)
+	rootCmd.AddCommand(cleanupCmd)
+}
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup [policy]...",
+	Short: "Leave, archive or mute stale channels using the configured cleanup policies",
+	Long:  "Runs the cleanup policies from the config over every channel in the sidebar. Policies use the same match rules as smart sections and the first matching policy applies to a channel. The planned actions are printed and need confirmation before anything is changed. Every action is written to a log so a run can be reversed with cleanup undo. If policy names are passed only those policies are run.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policies := config.GetConfig().CleanupPolicies
+		if len(args) > 0 {
+			policies = []config.CleanupPolicy{}
+			for _, name := range args {
+				i := slices.IndexFunc(config.GetConfig().CleanupPolicies, func(p config.CleanupPolicy) bool { return p.Name == name })
+				if i < 0 {
+					return fmt.Errorf("cleanup policy %s not found", name)
+				}
+				policies = append(policies, config.GetConfig().CleanupPolicies[i])
+			}
+		}
+		if len(policies) == 0 {
+			return errors.New("no cleanup policies configured, add cleanup_policies to ~/.config/slackcli.yaml")
+		}
+
+		actions, err := slackutils.PlanCleanup(policies)
+		if err != nil {
+			return err
+		}
+
+		if cleanupDryRun || len(actions) == 0 {
+			if jsonOutput {
+				return printJSON(actions)
+			}
+			if len(actions) == 0 {
+				fmt.Println("Nothing to clean up")
+			}
+			printCleanupActions(actions)
+			return nil
+		}
+
+		if !cleanupYes {
+			printCleanupActions(actions)
+			ok, err := confirm(fmt.Sprintf("Apply %d cleanup actions?", len(actions)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("cleanup cancelled")
+			}
+		}
+
+		run, err := slackutils.ExecuteCleanup(actions, cleanupLogFile)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(run)
+		}
+
+		fmt.Printf("Cleanup run %s done, undo it with: slack-cli cleanup undo %s\n", run.ID, run.ID)
+		return cleanupErrors(run.Actions)
+	},
+}
+
+var cleanupUndoCmd = &cobra.Command{
+	Use:   "undo [run-id]",
+	Short: "Reverse a cleanup run, the latest one by default",
+	Long:  "Rejoins left channels, unarchives archived channels and unmutes muted channels of a cleanup run. A run is only marked undone once every action has been reversed, run undo again to retry reversals that failed.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := ""
+		if len(args) > 0 {
+			id = args[0]
+		}
+
+		if !cleanupYes {
+			log, err := slackutils.LoadCleanupLog(cleanupLogFile)
+			if err != nil {
+				return err
+			}
+			run, err := log.Run(id)
+			if err != nil {
+				return err
+			}
+
+			printCleanupActions(run.Actions)
+			ok, err := confirm(fmt.Sprintf("Undo cleanup run %s?", run.ID))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("undo cancelled")
+			}
+		}
+
+		_, undone, err := slackutils.UndoCleanup(id, cleanupLogFile)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(undone)
+		}
+
+		printCleanupActions(undone)
+		return cleanupErrors(undone)
+	},
+}
+
+var cleanupLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List previous cleanup runs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log, err := slackutils.LoadCleanupLog(cleanupLogFile)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(log.Runs)
+		}
+
+		for _, run := range log.Runs {
+			undone := ""
+			if run.UndoneAt != nil {
+				undone = fmt.Sprintf(" (undone %s)", run.UndoneAt.Local().Format(time.DateTime))
+			}
+			fmt.Printf("%s  %s  %d actions%s\n", run.ID, run.Time.Local().Format(time.DateTime), len(run.Actions), undone)
+		}
+		return nil
+	},
+}
+
+func printCleanupActions(actions []slackutils.CleanupAction) {
+	for _, a := range actions {
+		if a.Error != "" {
+			fmt.Printf("%-10s #%s (%s): failed: %s\n", a.Action, a.ChannelName, a.Policy, a.Error)
+			continue
+		}
+		fmt.Printf("%-10s #%s (%s)\n", a.Action, a.ChannelName, a.Policy)
+	}
+}
+
+func cleanupErrors(actions []slackutils.CleanupAction) error {
+	failed := 0
+	for _, a := range actions {
+		if a.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d actions failed", failed, len(actions))
+	}
+	return nil
+}
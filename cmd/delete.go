@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <channel> <ts>",
+	Short: "Delete a sent message",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		_, _, err = config.SlackClient.DeleteMessage(channel, args[1])
+		return err
+	},
+}
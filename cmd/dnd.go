@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	dndCmd.AddCommand(dndSnoozeCmd)
+	dndCmd.AddCommand(dndEndCmd)
+	rootCmd.AddCommand(dndCmd)
+}
+
+var dndCmd = &cobra.Command{
+	Use:   "dnd",
+	Short: "Pause and resume notifications",
+}
+
+var dndSnoozeCmd = &cobra.Command{
+	Use:   "snooze <duration|time>",
+	Short: "Pause notifications for a duration such as 1h or until a time such as 5pm",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now().In(slackLocation())
+		until, err := slackutils.ParseFutureTime(args[0], now)
+		if err != nil {
+			return err
+		}
+
+		minutes := int(math.Ceil(until.Sub(now).Minutes()))
+		if minutes < 1 {
+			return fmt.Errorf("snooze end %s is in the past", until.Format(time.RFC1123))
+		}
+
+		status, err := config.SlackClient.SetSnooze(minutes)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(status)
+		}
+
+		fmt.Printf("Notifications paused until %s\n", time.Unix(int64(status.SnoozeEndTime), 0).In(now.Location()).Format("Mon Jan 2 15:04 MST"))
+		return nil
+	},
+}
+
+var dndEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "Resume notifications, ending a snooze or the current do not disturb session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := config.SlackClient.GetDNDInfo(nil)
+		if err != nil {
+			return err
+		}
+
+		if info.SnoozeEnabled {
+			_, err := config.SlackClient.EndSnooze()
+			return err
+		}
+
+		return config.SlackClient.EndDND()
+	},
+}
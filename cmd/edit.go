@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit <channel> <ts> <message>",
+	Short: "Replace the text of a sent message",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		message, err := readMessageArg(cmd, args[2])
+		if err != nil {
+			return err
+		}
+
+		channel, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		_, _, _, err = config.SlackClient.UpdateMessage(channel, args[1], slack.MsgOptionText(message, false))
+		return err
+	},
+}
@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+var exportOutputFile string
+var exportFilesDir string
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "jsonl", "Export format (jsonl, markdown, html)")
+	exportCmd.Flags().StringVarP(&exportOutputFile, "output", "o", "", "File to write the export to. Defaults to stdout")
+	exportCmd.Flags().StringVar(&exportFilesDir, "files", "", "Download attached files into this directory")
+	rootCmd.AddCommand(exportCmd)
+}
+
+type exportedFile struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Mimetype  string `json:"mimetype"`
+	URL       string `json:"url"`
+	LocalPath string `json:"local_path,omitempty"`
+}
+
+type exportedMessage struct {
+	Timestamp       string               `json:"ts"`
+	ThreadTimestamp string               `json:"thread_ts,omitempty"`
+	Time            time.Time            `json:"time"`
+	UserID          string               `json:"user_id"`
+	UserName        string               `json:"user_name"`
+	Text            string               `json:"text"`
+	RawText         string               `json:"raw_text"`
+	Files           []exportedFile       `json:"files,omitempty"`
+	Reactions       []slack.ItemReaction `json:"reactions,omitempty"`
+	Replies         []exportedMessage    `json:"-"`
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <channel>",
+	Short: "Export the full history of a channel",
+	Long:  "Walks the full history of a channel including all thread replies and writes it out as json lines, a markdown transcript or a self contained html page. User mentions and emoji are resolved and attached files can optionally be downloaded.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var write func(io.Writer, string, []exportedMessage, *slackutils.UserCache, *slackutils.EmojiCache) error
+		switch exportFormat {
+		case "jsonl", "json":
+			write = writeExportJSONL
+		case "markdown", "md":
+			write = writeExportMarkdown
+		case "html":
+			write = writeExportHTML
+		default:
+			return errors.New("valid export formats are jsonl, markdown or html")
+		}
+
+		channelID, err := slackutils.ParseChannelTarget(args[0])
+		if err != nil {
+			return err
+		}
+
+		users := slackutils.NewUserCache()
+		emoji := slackutils.NewEmojiCache(nil)
+		if err := emoji.LoadCustom(); err != nil {
+			logrus.WithError(err).Warn("could not fetch workspace emoji")
+		}
+
+		history, err := slackutils.GetFullHistory(channelID)
+		if err != nil {
+			return err
+		}
+
+		plain := slackutils.TextFormatter{Users: users, Emoji: emoji}
+		messages := make([]exportedMessage, 0, len(history))
+		for _, m := range history {
+			exported := exportMessage(m, users, plain)
+
+			if m.ReplyCount > 0 {
+				logrus.WithField("thread", m.Timestamp).Debug("fetching thread replies")
+				replies, err := slackutils.GetAllThreadReplies(channelID, m.Timestamp)
+				if err != nil {
+					return err
+				}
+
+				for _, r := range replies {
+					exported.Replies = append(exported.Replies, exportMessage(r, users, plain))
+				}
+			}
+
+			messages = append(messages, exported)
+		}
+
+		out := cmd.OutOrStdout()
+		if exportOutputFile != "" {
+			f, err := os.Create(exportOutputFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		return write(out, args[0], messages, users, emoji)
+	},
+}
+
+func exportMessage(m slack.Message, users *slackutils.UserCache, plain slackutils.TextFormatter) exportedMessage {
+	exported := exportedMessage{
+		Timestamp: m.Timestamp,
+		Time:      slackutils.ParseTimestamp(m.Timestamp),
+		UserID:    m.User,
+		Text:      plain.Format(m.Text),
+		RawText:   m.Text,
+		Reactions: m.Reactions,
+	}
+
+	if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+		exported.ThreadTimestamp = m.ThreadTimestamp
+	}
+
+	switch {
+	case m.User != "":
+		name, err := users.Resolve(m.User)
+		if err != nil {
+			logrus.WithError(err).WithField("user", m.User).Debug("could not resolve user")
+			name = m.User
+		}
+		exported.UserName = name
+	case m.Username != "":
+		exported.UserName = m.Username
+	case m.BotProfile != nil:
+		exported.UserName = m.BotProfile.Name
+	}
+
+	for _, f := range m.Files {
+		file := exportedFile{
+			ID:       f.ID,
+			Name:     f.Name,
+			Title:    f.Title,
+			Mimetype: f.Mimetype,
+			URL:      f.URLPrivateDownload,
+		}
+		if file.URL == "" {
+			file.URL = f.URLPrivate
+		}
+
+		// A failed download keeps the remote url so one file does not lose the whole transcript
+		if exportFilesDir != "" && file.URL != "" {
+			path, err := downloadExportFile(file)
+			if err != nil {
+				logrus.WithError(err).WithField("file", file.Name).Warn("could not download file, linking to slack instead")
+			}
+			file.LocalPath = path
+		}
+
+		exported.Files = append(exported.Files, file)
+	}
+
+	return exported
+}
+
+func downloadExportFile(file exportedFile) (string, error) {
+	if err := os.MkdirAll(exportFilesDir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(exportFilesDir, file.ID+"-"+filepath.Base(file.Name))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	logrus.WithField("file", file.Name).WithField("path", path).Debug("downloading file")
+	if err := config.SlackClient.GetFile(file.URL, f); err != nil {
+		// Do not leave a partial file behind that looks like a complete download
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Link to an attached file, pointing at the downloaded copy relative to the export file when there is one
+func exportFileLink(file exportedFile) string {
+	if file.LocalPath == "" {
+		return file.URL
+	}
+
+	base := "."
+	if exportOutputFile != "" {
+		base = filepath.Dir(exportOutputFile)
+	}
+
+	target := file.LocalPath
+	absBase, baseErr := filepath.Abs(base)
+	absTarget, targetErr := filepath.Abs(file.LocalPath)
+	if baseErr == nil && targetErr == nil {
+		if rel, err := filepath.Rel(absBase, absTarget); err == nil {
+			target = rel
+		}
+	}
+
+	return (&url.URL{Path: filepath.ToSlash(target)}).String()
+}
+
+func writeExportJSONL(w io.Writer, _ string, messages []exportedMessage, _ *slackutils.UserCache, _ *slackutils.EmojiCache) error {
+	enc := json.NewEncoder(w)
+	for _, m := range messages {
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+		for _, r := range m.Replies {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeExportMarkdown(w io.Writer, channel string, messages []exportedMessage, users *slackutils.UserCache, emoji *slackutils.EmojiCache) error {
+	formatter := slackutils.TextFormatter{
+		Users: users,
+		Emoji: emoji,
+		Link: func(url string, label string) string {
+			return fmt.Sprintf("[%s](%s)", label, url)
+		},
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n_Exported on %s_\n", channel, time.Now().Format("2006-01-02 15:04 MST"))
+
+	writeMessage := func(m exportedMessage, prefix string) {
+		sb.WriteString(prefix + "\n")
+		fmt.Fprintf(&sb, "%s**%s** · %s\n", prefix, m.UserName, m.Time.Format("2006-01-02 15:04"))
+		for _, line := range strings.Split(formatter.Format(m.RawText), "\n") {
+			sb.WriteString(prefix + line + "\n")
+		}
+		for _, f := range m.Files {
+			fmt.Fprintf(&sb, "%s📎 [%s](%s)\n", prefix, f.Name, exportFileLink(f))
+		}
+	}
+
+	for _, m := range messages {
+		writeMessage(m, "")
+		for _, r := range m.Replies {
+			writeMessage(r, "> ")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var exportHTMLTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Channel }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 900px; margin: 2em auto; color: #1d1c1d; }
+.message { padding: 0.4em 0; }
+.meta { margin-bottom: 0.2em; }
+.user { font-weight: bold; }
+.time { color: #616061; font-size: 0.85em; margin-left: 0.5em; }
+.text { white-space: pre-wrap; }
+.replies { margin-left: 1.5em; padding-left: 1em; border-left: 3px solid #ddd; }
+.files a { display: inline-block; margin-top: 0.2em; }
+</style>
+</head>
+<body>
+<h1>{{ .Channel }}</h1>
+<p class="time">Exported on {{ .Exported }}</p>
+{{ define "message" }}<div class="message">
+<div class="meta"><span class="user">{{ .UserName }}</span><span class="time">{{ .Time.Format "2006-01-02 15:04" }}</span></div>
+<div class="text">{{ .HTML }}</div>
+{{ if .Files }}<div class="files">{{ range .Files }}<a href="{{ .Href }}">📎 {{ .Name }}</a> {{ end }}</div>{{ end }}
+</div>{{ end }}
+{{ range .Messages }}{{ template "message" . }}
+{{ if .Replies }}<div class="replies">{{ range .Replies }}{{ template "message" . }}{{ end }}</div>{{ end }}
+{{ end }}
+</body>
+</html>
+`))
+
+type htmlExportFile struct {
+	Name string
+	Href string
+}
+
+type htmlExportMessage struct {
+	UserName string
+	Time     time.Time
+	HTML     template.HTML
+	Files    []htmlExportFile
+	Replies  []htmlExportMessage
+}
+
+func writeExportHTML(w io.Writer, channel string, messages []exportedMessage, users *slackutils.UserCache, emoji *slackutils.EmojiCache) error {
+	formatter := slackutils.TextFormatter{
+		Users:  users,
+		Emoji:  emoji,
+		Escape: html.EscapeString,
+		Link: func(url string, label string) string {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(label))
+		},
+	}
+
+	convert := func(m exportedMessage) htmlExportMessage {
+		converted := htmlExportMessage{
+			UserName: m.UserName,
+			Time:     m.Time,
+			HTML:     template.HTML(formatter.Format(m.RawText)),
+		}
+		for _, f := range m.Files {
+			converted.Files = append(converted.Files, htmlExportFile{Name: f.Name, Href: exportFileLink(f)})
+		}
+		return converted
+	}
+
+	data := struct {
+		Channel  string
+		Exported string
+		Messages []htmlExportMessage
+	}{
+		Channel:  channel,
+		Exported: time.Now().Format("2006-01-02 15:04 MST"),
+	}
+
+	for _, m := range messages {
+		converted := convert(m)
+		for _, r := range m.Replies {
+			converted.Replies = append(converted.Replies, convert(r))
+		}
+		data.Messages = append(data.Messages, converted)
+	}
+
+	return exportHTMLTemplate.Execute(w, data)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+var mentionsSince string
+var mentionsLimit int
+var mentionsCompact bool
+
+func init() {
+	mentionsCmd.Flags().StringVarP(&mentionsSince, "since", "s", "1d", "How far back to look for mentions, such as 4h or 2d")
+	mentionsCmd.Flags().IntVarP(&mentionsLimit, "limit", "l", 50, "How many mentions to return total")
+	mentionsCmd.Flags().BoolVarP(&mentionsCompact, "compact", "c", false, "Print only the number of mentions for status bars")
+	rootCmd.AddCommand(mentionsCmd)
+}
+
+var mentionsCmd = &cobra.Command{
+	Use:   "mentions",
+	Short: "List recent messages that mention you",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := slackutils.ParseDuration(mentionsSince)
+		if err != nil {
+			return err
+		}
+
+		userID, err := slackutils.GetCurrentUserID()
+		if err != nil {
+			return err
+		}
+
+		mentions, err := slackutils.GetMentionsSince(userID, time.Now().Add(-since), mentionsLimit)
+		if err != nil {
+			return err
+		}
+
+		if mentionsCompact {
+			if jsonOutput {
+				return printJSON(map[string]int{"mentions": len(mentions)})
+			}
+			fmt.Printf("%d mentions\n", len(mentions))
+			return nil
+		}
+
+		if jsonOutput {
+			return printJSON(mentions)
+		}
+
+		for _, m := range mentions {
+			printSearchMessage(m)
+		}
+		return nil
+	},
+}
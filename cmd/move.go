@@ -1,21 +1,43 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/graytonio/slack-cli/lib/slackutils"
 	"github.com/spf13/cobra"
 )
 
+var moveDryRun bool
+
 func init() {
+	moveCmd.Flags().BoolVar(&moveDryRun, "dry-run", false, "Print which channels would move without changing the sidebar")
 	rootCmd.AddCommand(moveCmd)
 }
 
 var moveCmd = &cobra.Command{
-	Use:   "move <channel> <section>",
-	Short: "Move a channel to a new section",
-	Args:  cobra.ExactArgs(2),
+	Use:   "move <channel>... <section>",
+	Short: "Move channels to a new section",
+	Long:  "Moves channels to a section. Channels can be given by name, id or as a glob pattern such as \"inc-*\" (quote patterns so the shell does not expand them). All matching channels are moved with a single request.",
+	Args:  cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		channel := args[0]
-		section := args[1]
-		return slackutils.MoveChannelToSection(channel, section)
+		channels := args[:len(args)-1]
+		section := args[len(args)-1]
+
+		plan, err := slackutils.MoveChannelsToSection(channels, section, moveDryRun)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(plan)
+		}
+
+		if moveDryRun {
+			for _, m := range plan.Moves {
+				fmt.Printf("#%s: %s -> %s\n", m.ChannelName, m.FromSection, m.ToSection)
+			}
+		}
+
+		return nil
 	},
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+var notifyApplyDryRun bool
+
+func init() {
+	notifyApplyCmd.Flags().BoolVar(&notifyApplyDryRun, "dry-run", false, "Print the channels the policies would change without changing anything")
+
+	notifyCmd.AddCommand(notifyApplyCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
+
+var notifyCmd = &cobra.Command{
+	Use:       "notify <channel> [mute|unmute|mentions|all|nothing]",
+	Short:     "Show or change the notification settings of a channel",
+	Long:      "Shows the notification settings of a channel, or changes them. mute and unmute keep the notification level, mentions, all and nothing set the level for both desktop and mobile.",
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: slackutils.NotifySettings,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channelID, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 2 {
+			setting := strings.ToLower(args[1])
+			if !slices.Contains(slackutils.NotifySettings, setting) {
+				return fmt.Errorf("invalid setting %q, expected one of %s", args[1], strings.Join(slackutils.NotifySettings, ", "))
+			}
+			return slackutils.SetChannelNotifications(channelID, setting)
+		}
+
+		prefs, err := slackutils.GetChannelNotificationPrefs()
+		if err != nil {
+			return err
+		}
+
+		channelPrefs := prefs[channelID]
+		if jsonOutput {
+			return printJSON(channelPrefs)
+		}
+
+		fmt.Printf("Muted: %t\n", channelPrefs.Muted)
+		fmt.Printf("Desktop: %s\n", notifyLevelName(channelPrefs.Desktop))
+		fmt.Printf("Mobile: %s\n", notifyLevelName(channelPrefs.Mobile))
+		return nil
+	},
+}
+
+var notifyApplyCmd = &cobra.Command{
+	Use:   "apply [policy]...",
+	Short: "Apply the configured notification policies to every matching channel",
+	Long:  "Runs the notification policies from the config over every channel in the sidebar. Policies use the same match rules as smart sections and the first matching policy applies to a channel. Channels that already have the setting are skipped. If policy names are passed only those policies are run.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policies := config.GetConfig().NotifyPolicies
+		if len(args) > 0 {
+			policies = []config.NotificationPolicy{}
+			for _, name := range args {
+				i := slices.IndexFunc(config.GetConfig().NotifyPolicies, func(p config.NotificationPolicy) bool { return p.Name == name })
+				if i < 0 {
+					return fmt.Errorf("notification policy %s not found", name)
+				}
+				policies = append(policies, config.GetConfig().NotifyPolicies[i])
+			}
+		}
+		if len(policies) == 0 {
+			return errors.New("no notification policies configured, add notification_policies to ~/.config/slackcli.yaml")
+		}
+
+		changes, err := slackutils.PlanNotificationPolicies(policies)
+		if err != nil {
+			return err
+		}
+
+		if !notifyApplyDryRun {
+			changes = slackutils.ApplyNotificationChanges(changes)
+		}
+
+		if jsonOutput {
+			return printJSON(changes)
+		}
+
+		failed := 0
+		for _, c := range changes {
+			if c.Error != "" {
+				failed++
+				fmt.Printf("%-8s #%s (%s): failed: %s\n", c.Setting, c.ChannelName, c.Policy, c.Error)
+				continue
+			}
+			fmt.Printf("%-8s #%s (%s)\n", c.Setting, c.ChannelName, c.Policy)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d changes failed", failed, len(changes))
+		}
+		return nil
+	},
+}
+
+// Channels without their own level follow the workspace default
+func notifyLevelName(level string) string {
+	if level == "" {
+		return "default"
+	}
+	return level
+}
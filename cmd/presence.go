@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/spf13/cobra"
+)
+
+var presenceValues = []string{"away", "auto"}
+
+func init() {
+	rootCmd.AddCommand(presenceCmd)
+}
+
+var presenceCmd = &cobra.Command{
+	Use:       "presence <away|auto>",
+	Short:     "Set your presence to away or let slack decide automatically",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: presenceValues,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(presenceValues, args[0]) {
+			return fmt.Errorf("invalid presence %q, expected away or auto", args[0])
+		}
+
+		return config.SlackClient.SetUserPresence(args[0])
+	},
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(reactCmd)
+	rootCmd.AddCommand(unreactCmd)
+	rootCmd.AddCommand(reactionsCmd)
+}
+
+type reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
+}
+
+// Validate emoji arguments against standard and workspace emoji, returning their names without colons.
+// When the workspace emoji can not be fetched only empty names are rejected and slack checks the rest
+func parseEmojiArgs(args []string) ([]string, error) {
+	emoji := slackutils.NewEmojiCache(nil)
+	loaded := true
+	if err := emoji.LoadCustom(); err != nil {
+		logrus.WithError(err).Warn("could not fetch workspace emoji")
+		loaded = false
+	}
+
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		name := strings.Trim(arg, ":")
+		if name == "" {
+			return nil, fmt.Errorf("invalid emoji %q", arg)
+		}
+		if loaded && !emoji.Has(name) {
+			return nil, unknownEmoji(emoji, name)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// Turn the errors slack returns for unknown emoji into an error suggesting similar names. This
+// catches emoji the local check let through, such as when the workspace emoji could not be fetched
+func emojiError(name string, err error) error {
+	if err == nil || (err.Error() != "invalid_name" && err.Error() != "profile_status_set_failed_not_valid_emoji") {
+		return err
+	}
+
+	emoji := slackutils.NewEmojiCache(nil)
+	if err := emoji.LoadCustom(); err != nil {
+		logrus.WithError(err).Debug("could not fetch workspace emoji for suggestions")
+	}
+	return unknownEmoji(emoji, name)
+}
+
+func unknownEmoji(emoji *slackutils.EmojiCache, name string) error {
+	suggestions := emoji.Suggest(name, 5)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("unknown emoji :%s:", name)
+	}
+	return fmt.Errorf("unknown emoji :%s: did you mean :%s:", name, strings.Join(suggestions, ": :"))
+}
+
+var reactCmd = &cobra.Command{
+	Use:   "react <channel> <ts> <:emoji:>...",
+	Short: "Add reactions to a message",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := parseEmojiArgs(args[2:])
+		if err != nil {
+			return err
+		}
+
+		channel, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			err := config.SlackClient.AddReaction(name, slack.NewRefToMessage(channel, args[1]))
+			if err != nil && err.Error() != "already_reacted" {
+				return emojiError(name, err)
+			}
+		}
+
+		return nil
+	},
+}
+
+var unreactCmd = &cobra.Command{
+	Use:   "unreact <channel> <ts> <:emoji:>...",
+	Short: "Remove reactions from a message",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := parseEmojiArgs(args[2:])
+		if err != nil {
+			return err
+		}
+
+		channel, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			err := config.SlackClient.RemoveReaction(name, slack.NewRefToMessage(channel, args[1]))
+			if err != nil && err.Error() != "no_reaction" {
+				return emojiError(name, err)
+			}
+		}
+
+		return nil
+	},
+}
+
+var reactionsCmd = &cobra.Command{
+	Use:   "reactions <channel> <ts>",
+	Short: "List reactions on a message and who reacted",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		items, err := config.SlackClient.GetReactions(slack.NewRefToMessage(channel, args[1]), slack.GetReactionsParameters{Full: true})
+		if err != nil {
+			return err
+		}
+
+		users := slackutils.NewUserCache()
+		reactions := make([]reaction, 0, len(items))
+		for _, item := range items {
+			r := reaction{Name: item.Name, Count: item.Count}
+			for _, id := range item.Users {
+				name, err := users.Resolve(id)
+				if err != nil {
+					logrus.WithError(err).WithField("user", id).Debug("could not resolve user")
+					name = id
+				}
+				r.Users = append(r.Users, name)
+			}
+			reactions = append(reactions, r)
+		}
+
+		if jsonOutput {
+			return printJSON(reactions)
+		}
+
+		for _, r := range reactions {
+			fmt.Printf(":%s: %d %s\n", r.Name, r.Count, strings.Join(r.Users, ", "))
+		}
+
+		return nil
+	},
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -32,3 +39,40 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// Print a value as indented json to stdout. Used by commands honouring --json
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Ask the user a yes/no question on the terminal. The terminal is used directly so
+// prompts work while stdin is piped; when there is no terminal an error is returned
+func confirm(question string) (bool, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, errors.New("confirmation required but no terminal is available, pass --yes to skip")
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// Get the timezone of the authenticated slack user so times given on the command line
+// mean the same thing as in the slack client, falling back to the local timezone
+func slackLocation() *time.Location {
+	loc, err := slackutils.GetUserLocation()
+	if err != nil {
+		logrus.WithError(err).Warn("could not get slack timezone, using local timezone")
+		return time.Local
+	}
+	return loc
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	scheduledCmd.AddCommand(scheduledListCmd)
+	scheduledCmd.AddCommand(scheduledCancelCmd)
+	rootCmd.AddCommand(scheduledCmd)
+}
+
+var scheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "Manage scheduled messages",
+}
+
+var scheduledListCmd = &cobra.Command{
+	Use:   "list [channel]",
+	Short: "List pending scheduled messages",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel := ""
+		if len(args) == 1 {
+			var err error
+			channel, err = slackutils.ResolveConversationID(args[0])
+			if err != nil {
+				return err
+			}
+		}
+
+		messages, err := slackutils.GetAllScheduledMessages(channel)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(messages)
+		}
+
+		loc := slackLocation()
+
+		for _, m := range messages {
+			fmt.Printf("%s  %s  %s  %s\n",
+				m.ID,
+				m.Channel,
+				time.Unix(int64(m.PostAt), 0).In(loc).Format("Mon Jan 2 15:04 MST"),
+				strings.ReplaceAll(m.Text, "\n", " "),
+			)
+		}
+
+		return nil
+	},
+}
+
+var scheduledCancelCmd = &cobra.Command{
+	Use:   "cancel <channel> <id>...",
+	Short: "Cancel scheduled messages",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel, err := slackutils.ResolveConversationID(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, id := range args[1:] {
+			_, err := config.SlackClient.DeleteScheduledMessage(&slack.DeleteScheduledMessageParameters{
+				Channel:            channel,
+				ScheduledMessageID: id,
+			})
+			if err != nil {
+				return fmt.Errorf("could not cancel %s: %w", id, err)
+			}
+		}
+
+		return nil
+	},
+}
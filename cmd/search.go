@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var searchFilters slackutils.SearchFilters
+var searchLimit int
+var searchSort string
+var searchFiles bool
+
+func init() {
+	searchCmd.Flags().StringSliceVar(&searchFilters.In, "in", nil, "Only search in this channel or conversation. Can be repeated")
+	searchCmd.Flags().StringSliceVar(&searchFilters.From, "from", nil, "Only search messages from this user. Can be repeated")
+	searchCmd.Flags().StringVar(&searchFilters.After, "after", "", "Only search messages after this date (YYYY-MM-DD, yesterday, ...)")
+	searchCmd.Flags().StringVar(&searchFilters.Before, "before", "", "Only search messages before this date (YYYY-MM-DD, today, ...)")
+	searchCmd.Flags().StringVar(&searchFilters.On, "on", "", "Only search messages on this date (YYYY-MM-DD, today, ...)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 20, "How many results to return total")
+	searchCmd.Flags().StringVar(&searchSort, "sort", "score", "Sort results by score or timestamp")
+	searchCmd.Flags().BoolVar(&searchFiles, "files", false, "Search files instead of messages")
+	rootCmd.AddCommand(searchCmd)
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search messages or files",
+	Long:  "Searches messages (or files with --files) using the slack search api. Filter flags are converted to slack search modifiers and appended to the query, so modifiers can also be written directly in the query.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchSort != "score" && searchSort != "timestamp" {
+			return errors.New("valid sort options are score or timestamp")
+		}
+
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		query = slackutils.BuildSearchQuery(query, searchFilters)
+		if query == "" {
+			return errors.New("a query or at least one filter is required")
+		}
+
+		if searchFiles {
+			files, err := slackutils.SearchFiles(query, searchSort, searchLimit)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(files)
+			}
+
+			users := slackutils.NewUserCache()
+			for _, f := range files {
+				author, err := users.Resolve(f.User)
+				if err != nil {
+					author = f.User
+				}
+
+				fmt.Printf("[%s] %s (%s) %s\n    %s\n",
+					f.Created.Time().Format("2006-01-02 15:04"),
+					f.Name,
+					f.Filetype,
+					author,
+					f.Permalink,
+				)
+			}
+			return nil
+		}
+
+		matches, err := slackutils.SearchMessages(query, searchSort, searchLimit)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(matches)
+		}
+
+		for _, m := range matches {
+			printSearchMessage(m)
+		}
+
+		return nil
+	},
+}
+
+// Print a message search result on one line followed by its permalink
+func printSearchMessage(m slack.SearchMessage) {
+	author := m.Username
+	if author == "" {
+		author = m.User
+	}
+
+	fmt.Printf("[%s] #%s %s: %s\n    %s\n",
+		slackutils.ParseTimestamp(m.Timestamp).Format("2006-01-02 15:04"),
+		m.Channel.Name,
+		author,
+		strings.ReplaceAll(m.Text, "\n", " "),
+		m.Permalink,
+	)
+}
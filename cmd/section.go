@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+var sectionCreateEmoji string
+var sectionCreateBefore string
+var sectionDeleteYes bool
+
+func init() {
+	sectionCreateCmd.Flags().StringVarP(&sectionCreateEmoji, "emoji", "e", "", "Emoji shown next to the section name")
+	sectionCreateCmd.Flags().StringVar(&sectionCreateBefore, "before", "", "Name of the section to place the new section above")
+	sectionDeleteCmd.Flags().BoolVarP(&sectionDeleteYes, "yes", "y", false, "Skip the confirmation prompt when the section still has channels")
+
+	sectionCmd.AddCommand(sectionListCmd)
+	sectionCmd.AddCommand(sectionShowCmd)
+	sectionCmd.AddCommand(sectionCreateCmd)
+	sectionCmd.AddCommand(sectionRenameCmd)
+	sectionCmd.AddCommand(sectionDeleteCmd)
+	sectionCmd.AddCommand(sectionReorderCmd)
+	sectionCmd.AddCommand(sectionCollapseCmd)
+	sectionCmd.AddCommand(sectionExpandCmd)
+	sectionCmd.AddCommand(sectionEmptyCmd)
+	rootCmd.AddCommand(sectionCmd)
+}
+
+type sectionSummary struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Emoji    string `json:"emoji,omitempty"`
+	Expanded bool   `json:"expanded"`
+	Channels int    `json:"channels"`
+}
+
+type sectionChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var sectionCmd = &cobra.Command{
+	Use:     "section",
+	Aliases: []string{"sections"},
+	Short:   "Manage sidebar sections",
+}
+
+var sectionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sidebar sections in order with their channel counts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sections, err := slackutils.GetChannelSections()
+		if err != nil {
+			return err
+		}
+
+		summaries := []sectionSummary{}
+		for _, s := range slackutils.OrderSections(sections) {
+			summaries = append(summaries, sectionSummary{
+				ID:       s.ID,
+				Name:     s.Name,
+				Type:     s.Type,
+				Emoji:    s.Emoji,
+				Expanded: s.IsExpanded,
+				Channels: len(s.ChannelIdsPage.ChannelIDs),
+			})
+		}
+
+		if jsonOutput {
+			return printJSON(summaries)
+		}
+
+		for _, s := range summaries {
+			name := s.Name
+			if name == "" {
+				name = s.Type
+			}
+			if s.Emoji != "" {
+				name = fmt.Sprintf(":%s: %s", s.Emoji, name)
+			}
+			fmt.Printf("%s\t%-30s %4d channels\n", s.ID, name, s.Channels)
+		}
+		return nil
+	},
+}
+
+var sectionShowCmd = &cobra.Command{
+	Use:   "show <section>",
+	Short: "List the channels in a section",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, err := slackutils.GetSectionByName(args[0])
+		if err != nil {
+			return err
+		}
+
+		channels, err := slackutils.GetAllConversations()
+		if err != nil {
+			return err
+		}
+
+		names := map[string]string{}
+		for _, c := range channels {
+			names[c.ID] = c.Name
+		}
+
+		members := []sectionChannel{}
+		for _, id := range section.ChannelIdsPage.ChannelIDs {
+			members = append(members, sectionChannel{ID: id, Name: names[id]})
+		}
+
+		if jsonOutput {
+			return printJSON(members)
+		}
+
+		for _, c := range members {
+			fmt.Printf("%s\t#%s\n", c.ID, c.Name)
+		}
+		return nil
+	},
+}
+
+var sectionCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a section, reusing an existing section with the same name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sections, err := slackutils.GetChannelSections()
+		if err != nil {
+			return err
+		}
+
+		section, err := slackutils.EnsureSection(sections, config.SmartSection{
+			SectionName: args[0],
+			Emoji:       sectionCreateEmoji,
+			Before:      sectionCreateBefore,
+		})
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(section)
+		}
+
+		fmt.Println(section.ID)
+		return nil
+	},
+}
+
+var sectionRenameCmd = &cobra.Command{
+	Use:   "rename <section> <name>",
+	Short: "Rename a section",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, err := slackutils.GetSectionByName(args[0])
+		if err != nil {
+			return err
+		}
+
+		return slackutils.UpdateSection(section.ID, args[1], "", "")
+	},
+}
+
+var sectionDeleteCmd = &cobra.Command{
+	Use:   "delete <section>",
+	Short: "Delete a section, moving its channels back to Channels",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, err := slackutils.GetSectionByName(args[0])
+		if err != nil {
+			return err
+		}
+
+		if count := len(section.ChannelIdsPage.ChannelIDs); count > 0 && !sectionDeleteYes {
+			ok, err := confirm(fmt.Sprintf("Section %s has %d channels. Delete it?", section.Name, count))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("section not deleted")
+			}
+		}
+
+		return slackutils.DeleteSection(section.ID)
+	},
+}
+
+var sectionReorderCmd = &cobra.Command{
+	Use:   "reorder <section> <section>...",
+	Short: "Place sections in the given order, each directly above the next",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sections := make([]*slackutils.ChannelSection, len(args))
+		for i, name := range args {
+			section, err := slackutils.GetSectionByName(name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			sections[i] = section
+		}
+
+		// Work from the bottom up so every section is placed above one that is already in position
+		for i := len(sections) - 2; i >= 0; i-- {
+			if err := slackutils.UpdateSection(sections[i].ID, "", "", sections[i+1].ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+var sectionCollapseCmd = &cobra.Command{
+	Use:   "collapse <section>",
+	Short: "Collapse a section in the sidebar",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, err := slackutils.GetSectionByName(args[0])
+		if err != nil {
+			return err
+		}
+
+		return slackutils.SetSectionExpanded(section.ID, false)
+	},
+}
+
+var sectionExpandCmd = &cobra.Command{
+	Use:   "expand <section>",
+	Short: "Expand a section in the sidebar",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, err := slackutils.GetSectionByName(args[0])
+		if err != nil {
+			return err
+		}
+
+		return slackutils.SetSectionExpanded(section.ID, true)
+	},
+}
+
+var sectionEmptyCmd = &cobra.Command{
+	Use:   "empty <section>",
+	Short: "Move every channel in a section back to Channels",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sections, err := slackutils.GetChannelSections()
+		if err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(sections, func(s slackutils.ChannelSection) bool { return s.Name == args[0] })
+		if i == -1 {
+			return slackutils.ErrSectionNotFound
+		}
+		section := sections[i]
+
+		plan, err := slackutils.EmptySection(sections, section)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(plan)
+		}
+
+		for _, m := range plan.Moves {
+			fmt.Printf("#%s: %s -> %s\n", m.ChannelName, m.FromSection, m.ToSection)
+		}
+		fmt.Printf("Moved %d channels out of %s\n", len(plan.Moves), section.Name)
+		return nil
+	},
+}
@@ -1,43 +1,640 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/graytonio/slack-cli/lib/config"
 	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 	"github.com/spf13/cobra"
 )
 
+var sendPrintTS bool
+var sendThreadTS string
+var sendFiles []string
+var sendFilename string
+var sendAt string
+var sendIn string
+var sendMarkdown bool
+var sendBlocksFile string
+var sendTemplate string
+var sendVarArgs []string
+var sendDataFile string
+var sendDryRun bool
+var sendYes bool
+var sendTo []string
+var sendToFile string
+var sendParallel int
+
 func init() {
+	sendCmd.Flags().BoolVar(&sendPrintTS, "print-ts", false, "Print the timestamp of the sent message so it can be edited or deleted later. Prints the scheduled message id when scheduling")
+	sendCmd.Flags().StringVarP(&sendThreadTS, "thread", "t", "", "Timestamp of a message to reply to in a thread")
+	sendCmd.Flags().StringArrayVarP(&sendFiles, "file", "f", nil, "File to upload. Can be repeated, use - to read from stdin")
+	sendCmd.Flags().StringVar(&sendFilename, "filename", "", "Name of the file uploaded from stdin")
+	sendCmd.Flags().StringVar(&sendAt, "at", "", "Schedule the message for a time such as \"tomorrow 9am\" in your slack timezone")
+	sendCmd.Flags().StringVar(&sendIn, "in", "", "Schedule the message after a delay such as 2h or 3d")
+	sendCmd.Flags().BoolVar(&sendMarkdown, "markdown", false, "Convert the message from markdown into blocks with headers, rich text lists and code blocks, using mrkdwn text as the fallback")
+	sendCmd.Flags().StringVar(&sendBlocksFile, "blocks", "", "Block Kit json file to send, use - to read from stdin. The message is used as fallback text")
+	sendCmd.Flags().StringVar(&sendTemplate, "template", "", "Render the message from a template in the config file")
+	sendCmd.Flags().StringArrayVar(&sendVarArgs, "var", nil, "Template variable in the form key=value. Can be repeated")
+	sendCmd.Flags().StringVar(&sendDataFile, "data", "", "Json file of template variables, use - to read from stdin")
+	sendCmd.Flags().BoolVar(&sendDryRun, "dry-run", false, "Resolve the target and print what would be sent without sending")
+	sendCmd.Flags().BoolVarP(&sendYes, "yes", "y", false, "Skip the confirmation prompt for broadcast mentions and large channels")
+	sendCmd.Flags().StringArrayVar(&sendTo, "to", nil, "Target to send to. Can be repeated to send to multiple targets")
+	sendCmd.Flags().StringVar(&sendToFile, "to-file", "", "File with one target per line to send to. Blank lines and lines starting with // are ignored")
+	sendCmd.Flags().IntVar(&sendParallel, "parallel", 4, "How many targets to send to at a time")
+	sendCmd.MarkFlagsMutuallyExclusive("at", "in")
+	sendCmd.MarkFlagsMutuallyExclusive("blocks", "file")
+	sendCmd.MarkFlagsMutuallyExclusive("blocks", "markdown")
 	rootCmd.AddCommand(sendCmd)
 }
 
+type sentMessage struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"ts"`
+}
+
+// The outcome of delivering a message to one target
+type sendResult struct {
+	Target             *sendTarget `json:"target"`
+	Timestamp          string      `json:"ts,omitempty"`
+	ScheduledMessageID string      `json:"scheduled_message_id,omitempty"`
+	Error              string      `json:"error,omitempty"`
+}
+
+type sendFile struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Size    int    `json:"size"`
+	content []byte
+}
+
+// A message that has been fully prepared from the command arguments and is ready to deliver
+type outgoingMessage struct {
+	Text      string        `json:"text"`
+	Blocks    []slack.Block `json:"blocks,omitempty"`
+	Files     []sendFile    `json:"files,omitempty"`
+	ThreadTS  string        `json:"thread_ts,omitempty"`
+	PostAt    *time.Time    `json:"post_at,omitempty"`
+	Broadcast []string      `json:"broadcast,omitempty"`
+}
+
+// A resolved conversation to deliver a message to
+type sendTarget struct {
+	Target    string `json:"target"`
+	ChannelID string `json:"channel_id"`
+	Name      string `json:"name,omitempty"`
+	Members   int    `json:"members,omitempty"`
+}
+
+// Read the message argument, using stdin when the message is "-"
+func readMessageArg(cmd *cobra.Command, message string) (string, error) {
+	if message != "-" {
+		return message, nil
+	}
+
+	stdin, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", err
+	}
+	return string(stdin), nil
+}
+
 var sendCmd = &cobra.Command{
-	Use:   "send <to> <message>",
-	Short: "Send a message to a channel",
-	Args:  cobra.ExactArgs(2),
+	Use:   "send [to] [message]",
+	Short: "Send a message or files to a channel",
+	Long:  "Sends a message to a channel. When files are attached with --file the message is used as the comment on the upload and may be omitted. Messages containing @channel, @here or @everyone, or sent to channels with more members than confirm_member_threshold, ask for confirmation first. When targets are given with --to or --to-file every argument is treated as the message and the message is sent to all targets concurrently. --thread can only be used with a single target.",
+	Args:  cobra.RangeArgs(0, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		message := args[1]
+		targetArgs, args, err := sendTargetArgs(args)
+		if err != nil {
+			return err
+		}
+
+		// A thread timestamp only exists in the channel it was posted in
+		if sendThreadTS != "" && len(targetArgs) > 1 {
+			return errors.New("--thread can only be used with a single target")
+		}
 
-		if message == "-" {
-			stdin, err := io.ReadAll(cmd.InOrStdin())
+		msg, err := sendBuildMessage(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		targets, err := resolveSendTargets(targetArgs)
+		if err != nil {
+			return err
+		}
+
+		if sendDryRun {
+			return printSendPlan(targets, msg)
+		}
+
+		if !sendYes {
+			ok, err := sendConfirm(targets, msg)
 			if err != nil {
 				return err
 			}
-			message = string(stdin)
+			if !ok {
+				return errors.New("message not sent")
+			}
 		}
 
-		to, err := slackutils.ParseChannelTarget(args[0])
+		results := make([]sendResult, len(targets))
+		runParallel(len(targets), sendParallel, func(i int) {
+			results[i] = deliverMessage(targets[i], msg)
+		})
+
+		return printSendResults(results, msg)
+	},
+}
+
+// Split the positional arguments into targets and the message. With --to or --to-file
+// every positional argument is part of the message, otherwise the first is the target
+func sendTargetArgs(args []string) ([]string, []string, error) {
+	targets := slices.Clone(sendTo)
+
+	if sendToFile != "" {
+		data, err := os.ReadFile(sendToFile)
 		if err != nil {
-			return err
+			return nil, nil, err
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "//") {
+				continue
+			}
+			targets = append(targets, line)
+		}
+	}
+
+	if len(targets) > 0 {
+		if len(args) > 1 {
+			return nil, nil, errors.New("only a message can be passed as an argument when using --to or --to-file")
 		}
+		return targets, args, nil
+	}
 
-		_, _, _, err = config.SlackClient.SendMessage(to, slack.MsgOptionText(message, false))
+	if len(args) == 0 {
+		return nil, nil, errors.New("a target is required")
+	}
+
+	return args[:1], args[1:], nil
+}
+
+// Run fn for every index in [0, count) with at most limit calls running at once
+func runParallel(count int, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Build the outgoing message from the message argument (if any) and the send flags
+func sendBuildMessage(cmd *cobra.Command, args []string) (*outgoingMessage, error) {
+	if len(args) == 0 && len(sendFiles) == 0 && sendBlocksFile == "" && sendTemplate == "" {
+		return nil, errors.New("a message, template, blocks or at least one file is required")
+	}
+
+	if len(args) > 0 && sendTemplate != "" {
+		return nil, errors.New("a message can not be used with --template")
+	}
+
+	// Every --file - is counted as stdin can only be read once
+	stdinUses := 0
+	for _, file := range sendFiles {
+		if file == "-" {
+			stdinUses++
+		}
+	}
+	for _, used := range []bool{
+		len(args) > 0 && args[0] == "-",
+		sendBlocksFile == "-",
+		sendTemplate != "" && sendDataFile == "-",
+	} {
+		if used {
+			stdinUses++
+		}
+	}
+	if stdinUses > 1 {
+		return nil, errors.New("stdin can only be used for one of the message, template data, blocks or a file")
+	}
+
+	if len(sendFiles) > 0 {
+		if sendPrintTS {
+			return nil, errors.New("--print-ts is not supported when uploading files")
+		}
+		if sendAt != "" || sendIn != "" {
+			return nil, errors.New("files can not be scheduled")
+		}
+	}
+
+	msg := &outgoingMessage{ThreadTS: sendThreadTS}
+
+	if len(args) > 0 {
+		text, err := readMessageArg(cmd, args[0])
 		if err != nil {
-			return err
+			return nil, err
+		}
+		msg.Text = text
+	}
+
+	if sendTemplate != "" {
+		vars, err := templateVars(cmd, sendVarArgs, sendDataFile)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.Text, err = slackutils.RenderTemplate(sendTemplate, vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Uploads can not carry blocks so a message sent with files only gets the mrkdwn text
+	if sendMarkdown && msg.Text != "" {
+		if len(sendFiles) == 0 {
+			markdownBlocks := slackutils.MarkdownToBlocks(msg.Text)
+			if errs := slackutils.ValidateBlocks(markdownBlocks); len(errs) > 0 {
+				logrus.WithError(errs[0]).Warn("markdown does not fit in blocks, sending mrkdwn text only")
+			} else {
+				msg.Blocks = markdownBlocks.BlockSet
+			}
+		}
+		msg.Text = slackutils.MarkdownToMrkdwn(msg.Text)
+	}
+
+	blocks, err := sendReadBlocks(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks.BlockSet) > 0 {
+		msg.Blocks = blocks.BlockSet
+		if msg.Text == "" {
+			msg.Text = slackutils.BlocksFallbackText(blocks)
+		}
+	}
+
+	for _, file := range sendFiles {
+		f, err := sendReadFile(cmd, file)
+		if err != nil {
+			return nil, err
+		}
+		msg.Files = append(msg.Files, f)
+	}
+
+	if sendAt != "" || sendIn != "" {
+		postAt, err := sendScheduleTime()
+		if err != nil {
+			return nil, err
+		}
+		msg.PostAt = &postAt
+	}
+
+	msg.Broadcast = slackutils.BroadcastMentions(msg.Text + "\n" + slackutils.BlocksFallbackText(blocks))
+
+	return msg, nil
+}
+
+// Read, parse and validate the blocks passed with --blocks. Validation problems are printed before failing
+func sendReadBlocks(cmd *cobra.Command) (slack.Blocks, error) {
+	if sendBlocksFile == "" {
+		return slack.Blocks{}, nil
+	}
+
+	var data []byte
+	var err error
+	if sendBlocksFile == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(sendBlocksFile)
+	}
+	if err != nil {
+		return slack.Blocks{}, err
+	}
+
+	blocks, err := slackutils.ParseBlocks(data)
+	if err != nil {
+		return slack.Blocks{}, err
+	}
+
+	if errs := slackutils.ValidateBlocks(blocks); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return slack.Blocks{}, fmt.Errorf("%d problems found in blocks, nothing was sent", len(errs))
+	}
+
+	return blocks, nil
+}
+
+// Prepare a file passed with --file for upload. Files from stdin are read into memory
+func sendReadFile(cmd *cobra.Command, file string) (sendFile, error) {
+	if file == "-" {
+		if sendFilename == "" {
+			return sendFile{}, errors.New("--filename is required when uploading from stdin")
 		}
 
+		content, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return sendFile{}, err
+		}
+
+		return sendFile{Name: sendFilename, Size: len(content), content: content}, nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return sendFile{}, err
+	}
+
+	return sendFile{Name: filepath.Base(file), Path: file, Size: int(info.Size())}, nil
+}
+
+// Work out when to post a message scheduled with --at or --in
+func sendScheduleTime() (time.Time, error) {
+	now := time.Now().In(slackLocation())
+
+	var postAt time.Time
+	if sendIn != "" {
+		delay, err := slackutils.ParseDuration(sendIn)
+		if err != nil {
+			return time.Time{}, err
+		}
+		postAt = now.Add(delay)
+	} else {
+		var err error
+		postAt, err = slackutils.ParseTime(sendAt, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if !postAt.After(now) {
+		return time.Time{}, fmt.Errorf("scheduled time %s is in the past", postAt.Format(time.RFC1123))
+	}
+
+	return postAt, nil
+}
+
+// Resolve every target argument, failing if any target can not be found. Conversations and
+// users are only fetched once and shared between all targets
+func resolveSendTargets(args []string) ([]*sendTarget, error) {
+	resolver := slackutils.NewConversationResolver()
+	targets := make([]*sendTarget, len(args))
+	errs := make([]error, len(args))
+	runParallel(len(args), sendParallel, func(i int) {
+		targets[i], errs[i] = resolveSendTarget(resolver, args[i])
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve %s: %w", args[i], err)
+		}
+	}
+
+	return targets, nil
+}
+
+// Resolve a target argument to a conversation, looking up its name and member count when needed
+func resolveSendTarget(resolver *slackutils.ConversationResolver, arg string) (*sendTarget, error) {
+	channelID, name, err := resolver.Resolve(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &sendTarget{Target: arg, ChannelID: channelID, Name: name}
+
+	// Member counts are only needed for the confirmation threshold and the dry run
+	if config.GetConfig().ConfirmThreshold <= 0 && !sendDryRun {
+		return target, nil
+	}
+
+	info, err := config.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("channel", channelID).Debug("could not get channel info")
+		return target, nil
+	}
+
+	if !info.IsIM {
+		target.Name = info.Name
+		target.Members = info.NumMembers
+	}
+
+	return target, nil
+}
+
+func (t *sendTarget) String() string {
+	if t.Name == "" {
+		return fmt.Sprintf("%s (%s)", t.Target, t.ChannelID)
+	}
+	return fmt.Sprintf("#%s (%s)", t.Name, t.ChannelID)
+}
+
+// Print what would be sent to each target for --dry-run
+func printSendPlan(targets []*sendTarget, msg *outgoingMessage) error {
+	if jsonOutput {
+		return printJSON(struct {
+			Targets []*sendTarget    `json:"targets"`
+			Message *outgoingMessage `json:"message"`
+		}{targets, msg})
+	}
+
+	for _, target := range targets {
+		fmt.Printf("To:       %s\n", target)
+		if target.Members > 0 {
+			fmt.Printf("Members:  %d\n", target.Members)
+		}
+	}
+	if msg.ThreadTS != "" {
+		fmt.Printf("Thread:   %s\n", msg.ThreadTS)
+	}
+	if msg.PostAt != nil {
+		fmt.Printf("Schedule: %s\n", msg.PostAt.Format("Mon Jan 2 15:04 MST"))
+	}
+	if len(msg.Broadcast) > 0 {
+		fmt.Printf("Notifies: @%s\n", strings.Join(msg.Broadcast, ", @"))
+	}
+	for _, f := range msg.Files {
+		fmt.Printf("File:     %s (%d bytes)\n", f.Name, f.Size)
+	}
+	if len(msg.Blocks) > 0 {
+		fmt.Printf("Blocks:   %d\n", len(msg.Blocks))
+	}
+	fmt.Printf("Message:\n%s\n", msg.Text)
+
+	return nil
+}
+
+// Ask for confirmation when a message notifies a whole channel or a channel is larger than the configured threshold
+func sendConfirm(targets []*sendTarget, msg *outgoingMessage) (bool, error) {
+	reasons := []string{}
+	if len(msg.Broadcast) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the message mentions @%s", strings.Join(msg.Broadcast, ", @")))
+	}
+
+	threshold := config.GetConfig().ConfirmThreshold
+	for _, target := range targets {
+		if threshold > 0 && target.Members > threshold {
+			reasons = append(reasons, fmt.Sprintf("%s has %d members", target, target.Members))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return true, nil
+	}
+
+	to := targets[0].String()
+	if len(targets) > 1 {
+		to = fmt.Sprintf("%d targets", len(targets))
+	}
+
+	return confirm(fmt.Sprintf("Sending to %s: %s. Send anyway?", to, strings.Join(reasons, " and ")))
+}
+
+// Deliver a prepared message to a target as an upload, a scheduled message or a plain message
+func deliverMessage(target *sendTarget, msg *outgoingMessage) sendResult {
+	result := sendResult{Target: target}
+
+	var err error
+	switch {
+	case len(msg.Files) > 0:
+		err = sendUploadFiles(target.ChannelID, msg)
+	case msg.PostAt != nil:
+		var resp *slackutils.ScheduleMessageResponse
+		resp, err = slackutils.ScheduleMessage(target.ChannelID, *msg.PostAt, msg.Text, msg.ThreadTS, msg.Blocks)
+		if err == nil {
+			logrus.WithField("id", resp.ScheduledMessageID).WithField("post_at", msg.PostAt).Debug("scheduled message")
+			result.ScheduledMessageID = resp.ScheduledMessageID
+		}
+	default:
+		options := []slack.MsgOption{slack.MsgOptionText(msg.Text, false)}
+		if len(msg.Blocks) > 0 {
+			options = append(options, slack.MsgOptionBlocks(msg.Blocks...))
+		}
+		if msg.ThreadTS != "" {
+			options = append(options, slack.MsgOptionTS(msg.ThreadTS))
+		}
+
+		_, result.Timestamp, _, err = config.SlackClient.SendMessage(target.ChannelID, options...)
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithField("target", target.Target).Debug("send failed")
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Report the outcome of a send. A single target keeps the quiet output scripts rely on,
+// multiple targets get a line per target. Fails if any target failed
+func printSendResults(results []sendResult, msg *outgoingMessage) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	if len(results) == 1 {
+		r := results[0]
+		if r.Error != "" {
+			return errors.New(r.Error)
+		}
+
+		switch {
+		case msg.PostAt != nil && sendPrintTS && jsonOutput:
+			return printJSON(r)
+		case msg.PostAt != nil && sendPrintTS:
+			fmt.Println(r.ScheduledMessageID)
+		case msg.PostAt != nil:
+			fmt.Printf("Scheduled for %s\n", msg.PostAt.Format("Mon Jan 2 15:04 MST"))
+		case sendPrintTS && jsonOutput:
+			return printJSON(sentMessage{Channel: r.Target.ChannelID, Timestamp: r.Timestamp})
+		case sendPrintTS:
+			fmt.Println(r.Timestamp)
+		}
 		return nil
-	},
+	}
+
+	if jsonOutput {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Printf("failed  %s: %s\n", r.Target, r.Error)
+				continue
+			}
+
+			line := fmt.Sprintf("sent    %s", r.Target)
+			if sendPrintTS {
+				line += " " + r.Timestamp + r.ScheduledMessageID
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sends failed", failed, len(results))
+	}
+	return nil
+}
+
+// Upload every attached file. The message text is used as the comment on the first upload only
+func sendUploadFiles(channelID string, msg *outgoingMessage) error {
+	comment := msg.Text
+	for _, file := range msg.Files {
+		params := slack.UploadFileV2Parameters{
+			Channel:         channelID,
+			ThreadTimestamp: msg.ThreadTS,
+			InitialComment:  comment,
+			Filename:        file.Name,
+			Title:           file.Name,
+			FileSize:        file.Size,
+		}
+
+		if file.Path != "" {
+			params.File = file.Path
+		} else {
+			params.Reader = bytes.NewReader(file.content)
+		}
+
+		logrus.WithField("file", params.Filename).WithField("size", params.FileSize).Debug("uploading file")
+		if _, err := config.SlackClient.UploadFileV2(params); err != nil {
+			return err
+		}
+
+		comment = ""
+	}
+
+	return nil
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var sidebarExportOutput string
+var sidebarImportDryRun bool
+
+func init() {
+	sidebarExportCmd.Flags().StringVarP(&sidebarExportOutput, "output", "o", "", "File to write the layout to. Defaults to stdout")
+	sidebarImportCmd.Flags().BoolVar(&sidebarImportDryRun, "dry-run", false, "Print the changes that would be made without changing the sidebar")
+
+	sidebarCmd.AddCommand(sidebarExportCmd)
+	sidebarCmd.AddCommand(sidebarImportCmd)
+	rootCmd.AddCommand(sidebarCmd)
+}
+
+var sidebarCmd = &cobra.Command{
+	Use:   "sidebar",
+	Short: "Export and import the sidebar layout",
+}
+
+var sidebarExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the sections, their order, emoji and channels to yaml",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		layout, err := slackutils.ExportSidebar()
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(layout)
+		}
+
+		out := os.Stdout
+		if sidebarExportOutput != "" {
+			out, err = os.Create(sidebarExportOutput)
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+		}
+
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+		if err := enc.Encode(layout); err != nil {
+			return err
+		}
+		return enc.Close()
+	},
+}
+
+var sidebarImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Apply a layout written by sidebar export, use - to read from stdin",
+	Long:  "Applies a sidebar layout. Missing sections are created, emoji and order are updated and channels are moved into their sections with a single request. Sections and channels that are not in the layout are left where they are, and channels you are not a member of are skipped.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var data []byte
+		var err error
+		if args[0] == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		layout := slackutils.SidebarLayout{}
+		if err := yaml.Unmarshal(data, &layout); err != nil {
+			return fmt.Errorf("invalid layout: %w", err)
+		}
+
+		plan, err := slackutils.ImportSidebar(&layout, sidebarImportDryRun)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(plan)
+		}
+
+		for _, name := range plan.Create {
+			fmt.Printf("create  %s\n", name)
+		}
+		for _, name := range plan.Update {
+			fmt.Printf("update  %s\n", name)
+		}
+		for _, m := range plan.Moves {
+			fmt.Printf("move    #%s: %s -> %s\n", m.ChannelName, m.FromSection, m.ToSection)
+		}
+		if len(plan.Missing) > 0 {
+			fmt.Printf("skipped channels not found: %s\n", strings.Join(plan.Missing, ", "))
+		}
+
+		return nil
+	},
+}
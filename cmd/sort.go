@@ -1,7 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/graytonio/slack-cli/lib/config"
 	"github.com/graytonio/slack-cli/lib/slackutils"
@@ -9,7 +19,21 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var sortDryRun bool
+var sortWatch bool
+var sortInterval time.Duration
+var sortStateFile string
+var sortGenerateUnit bool
+
 func init() {
+	home, _ := os.UserHomeDir()
+
+	sortCmd.Flags().BoolVar(&sortDryRun, "dry-run", false, "Print which channels would move between sections without changing the sidebar")
+	sortCmd.Flags().BoolVarP(&sortWatch, "watch", "w", false, "Keep running and sort newly joined channels every interval")
+	sortCmd.Flags().DurationVarP(&sortInterval, "interval", "i", 5*time.Minute, "How often to check for newly joined channels with --watch")
+	sortCmd.Flags().StringVar(&sortStateFile, "state", path.Join(home, ".config/slackcli-sort-state.json"), "File recording the channels --watch has already sorted")
+	sortCmd.Flags().BoolVar(&sortGenerateUnit, "generate-unit", false, "Print a systemd user unit that runs sort --watch with the given arguments")
+	sortCmd.MarkFlagsMutuallyExclusive("dry-run", "watch")
 	rootCmd.AddCommand(sortCmd)
 }
 
@@ -21,27 +45,155 @@ var sortCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logrus.WithField("args", args).WithField("length", len(args)).Debug("sorting channels")
 
+		sections := []config.SmartSection{}
+
 		// Run all configured filters
 		if len(args) == 0 {
-			for _, s := range config.GetConfig().SmartSections {
-				err := slackutils.ExecuteSmartSection(s.SectionName, s.ReExpression)
-				if err != nil {
-					return err
-				}
-			}
-			return nil
+			sections = slices.Clone(config.GetConfig().SmartSections)
 			// Run Specific Filter
 		} else if len(args) == 1 {
 			for _, s := range config.GetConfig().SmartSections {
 				if s.SectionName == args[0] {
-					return slackutils.ExecuteSmartSection(args[0], s.ReExpression)
+					sections = append(sections, s)
+					break
 				}
 			}
+			if len(sections) == 0 {
+				return errors.New("no config found")
+			}
 			// Run AdHock Filter
 		} else if len(args) == 2 {
-			return slackutils.ExecuteSmartSection(args[0], args[1])
+			sections = append(sections, config.SmartSection{SectionName: args[0], ReExpression: args[1]})
+		}
+
+		if sortGenerateUnit {
+			return printSortUnit(args)
 		}
 
-		return errors.New("no config found")
+		if sortDryRun {
+			return printSortPlan(sections)
+		}
+
+		if sortWatch {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return slackutils.WatchSmartSections(ctx, sections, sortInterval, sortStateFile, printSortRun)
+		}
+
+		plan, err := slackutils.ExecuteSmartSections(sections)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(plan)
+		}
+
+		warnSortConflicts(plan)
+		return nil
 	},
 }
+
+func warnSortConflicts(plan *slackutils.SortPlan) {
+	for _, c := range plan.Conflicts {
+		logrus.WithField("sections", c.Sections).WithField("winner", c.Winner).Warnf("#%s matched multiple sections", c.ChannelName)
+	}
+}
+
+// Report the channels moved by one run of sort --watch
+func printSortRun(plan *slackutils.SortPlan) {
+	if jsonOutput {
+		if err := json.NewEncoder(os.Stdout).Encode(plan); err != nil {
+			logrus.WithError(err).Warn("could not write sort run")
+		}
+		return
+	}
+
+	now := time.Now().Format(time.DateTime)
+	for _, p := range plan.Sections {
+		for _, m := range p.Moves {
+			fmt.Printf("%s #%s: %s -> %s\n", now, m.ChannelName, m.FromSection, m.ToSection)
+		}
+	}
+	warnSortConflicts(plan)
+}
+
+var sortUnitTemplate = `[Unit]
+Description=Sort slack channels into smart sections
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=60
+
+[Install]
+WantedBy=default.target
+`
+
+// Print a systemd user unit running sort --watch with the same arguments and flags
+func printSortUnit(args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	command := []string{exe, "sort"}
+	command = append(command, args...)
+	command = append(command, "--watch", "--interval", sortInterval.String(), "--state", sortStateFile)
+
+	for i, arg := range command {
+		// systemd expands %specifiers and $VARIABLES even inside quotes
+		if strings.ContainsAny(arg, " \t\"'\\$%") {
+			command[i] = strconv.Quote(strings.NewReplacer("%", "%%", "$", "$$").Replace(arg))
+		}
+	}
+
+	fmt.Printf(sortUnitTemplate, strings.Join(command, " "))
+	return nil
+}
+
+// Plan every smart section against one snapshot of the sidebar and print the moves they would make
+func printSortPlan(smartSections []config.SmartSection) error {
+	channels, err := slackutils.GetAllConversations()
+	if err != nil {
+		return err
+	}
+
+	sections, err := slackutils.GetChannelSections()
+	if err != nil {
+		return err
+	}
+
+	plan, err := slackutils.PlanSmartSections(sections, channels, smartSections, slackutils.NewRuleContext())
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(plan)
+	}
+
+	for _, p := range plan.Sections {
+		header := p.Section
+		if p.CreateSection {
+			header += " (new section)"
+		}
+		fmt.Printf("%s: %d channels\n", header, len(p.Moves))
+
+		for _, m := range p.Moves {
+			fmt.Printf("  #%s: %s -> %s\n", m.ChannelName, m.FromSection, m.ToSection)
+		}
+	}
+
+	if len(plan.Conflicts) > 0 {
+		fmt.Println("Matched multiple sections:")
+		for _, c := range plan.Conflicts {
+			fmt.Printf("  #%s: %s -> %s\n", c.ChannelName, strings.Join(c.Sections, ", "), c.Winner)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+var statusExpires string
+
+func init() {
+	statusSetCmd.Flags().StringVarP(&statusExpires, "expires", "e", "", "Clear the status after a delay such as 3d or at a time such as \"friday 5pm\"")
+
+	statusCmd.AddCommand(statusSetCmd)
+	statusCmd.AddCommand(statusClearCmd)
+	statusCmd.AddCommand(statusGetCmd)
+	rootCmd.AddCommand(statusCmd)
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Manage your custom status",
+}
+
+var statusSetCmd = &cobra.Command{
+	Use:   "set <:emoji:> [text]",
+	Short: "Set your custom status",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := parseEmojiArgs(args[:1])
+		if err != nil {
+			return err
+		}
+
+		text := ""
+		if len(args) == 2 {
+			text = args[1]
+		}
+
+		var expiration time.Time
+		if statusExpires != "" {
+			now := time.Now().In(slackLocation())
+			expiration, err = slackutils.ParseFutureTime(statusExpires, now)
+			if err != nil {
+				return err
+			}
+			if !expiration.After(now) {
+				return fmt.Errorf("expiration %s is in the past", expiration.Format(time.RFC1123))
+			}
+		}
+
+		return emojiError(names[0], slackutils.SetStatus(names[0], text, expiration))
+	},
+}
+
+var statusClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear your custom status",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return config.SlackClient.UnsetUserCustomStatus()
+	},
+}
+
+var statusGetCmd = &cobra.Command{
+	Use:   "get [@user]",
+	Short: "Show the status, presence and do not disturb state of a user. Defaults to yourself",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var userID string
+		var err error
+		if len(args) == 1 {
+			userID, err = slackutils.ResolveUserID(args[0])
+		} else {
+			userID, err = slackutils.GetCurrentUserID()
+		}
+		if err != nil {
+			return err
+		}
+
+		status, err := slackutils.GetUserStatus(userID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(status)
+		}
+
+		fmt.Printf("User:     @%s (%s)\n", status.Name, status.UserID)
+		printUserStatus(status)
+		return nil
+	},
+}
+
+// Print the status, presence and do not disturb lines of a user
+func printUserStatus(status *slackutils.UserStatus) {
+	loc := slackLocation()
+	if status.Emoji != "" || status.Text != "" {
+		fmt.Printf("Status:   %s %s\n", status.Emoji, status.Text)
+	}
+	if status.Expiration != nil {
+		fmt.Printf("Expires:  %s\n", status.Expiration.In(loc).Format("Mon Jan 2 15:04 MST"))
+	}
+	if status.Presence != "" {
+		fmt.Printf("Presence: %s\n", status.Presence)
+	}
+	if status.DND && status.DNDEnd != nil {
+		fmt.Printf("DND:      until %s\n", status.DNDEnd.In(loc).Format("Mon Jan 2 15:04 MST"))
+	} else if status.DND {
+		fmt.Println("DND:      on")
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var tailLines int
+var tailInterval time.Duration
+var tailOutputFormat string
+
+func init() {
+	tailCmd.Flags().IntVarP(&tailLines, "lines", "n", 0, "How many recent messages to print before following")
+	tailCmd.Flags().DurationVarP(&tailInterval, "interval", "i", 5*time.Second, "How often to poll for new messages")
+	tailCmd.Flags().StringVar(&tailOutputFormat, "format", "${user_id}: ${text}", "Format to output messages in")
+	rootCmd.AddCommand(tailCmd)
+}
+
+type tailMessage struct {
+	Channel string        `json:"channel"`
+	Message slack.Message `json:"message"`
+}
+
+type tailTarget struct {
+	name     string
+	id       string
+	latestTS string
+}
+
+var tailCmd = &cobra.Command{
+	Use:     "tail <channel>...",
+	Aliases: []string{"follow"},
+	Short:   "Stream new messages from one or more channels",
+	Long:    "Prints new messages as they are posted to the given channels until interrupted. When more than one channel is followed each line is prefixed with the channel it came from.",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		targets := make([]*tailTarget, 0, len(args))
+		for _, arg := range args {
+			id, err := slackutils.ParseChannelTarget(arg)
+			if err != nil {
+				return err
+			}
+
+			// Seed the starting point from the latest message so only new messages are followed
+			limit := tailLines
+			if limit < 1 {
+				limit = 1
+			}
+			msgs, err := slackutils.GetRecentMessages(id, limit)
+			if err != nil {
+				return err
+			}
+
+			t := &tailTarget{name: arg, id: id}
+			if len(msgs) > 0 {
+				t.latestTS = msgs[0].Timestamp
+			} else {
+				t.latestTS = fmt.Sprintf("%d.000000", time.Now().Unix())
+			}
+			targets = append(targets, t)
+
+			if tailLines > 0 {
+				if err := printTailMessages(t, msgs, len(args) > 1); err != nil {
+					return err
+				}
+			}
+		}
+
+		ticker := time.NewTicker(tailInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				for _, t := range targets {
+					msgs, err := slackutils.GetMessagesSince(t.id, t.latestTS)
+					if err != nil {
+						logrus.WithError(err).WithField("channel", t.name).Warn("could not poll channel")
+						continue
+					}
+
+					if len(msgs) == 0 {
+						continue
+					}
+
+					t.latestTS = msgs[0].Timestamp
+					if err := printTailMessages(t, msgs, len(targets) > 1); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	},
+}
+
+// Print messages oldest first. Messages are expected in the newest first order the api returns
+func printTailMessages(t *tailTarget, msgs []slack.Message, prefix bool) error {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		m := msgs[i]
+
+		if jsonOutput {
+			line, err := json.Marshal(tailMessage{Channel: t.name, Message: m})
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(line))
+			continue
+		}
+
+		line := TSprintf(tailOutputFormat, map[string]any{
+			"channel":   t.name,
+			"user_id":   m.User,
+			"text":      m.Text,
+			"timestamp": m.Timestamp,
+		})
+		if prefix {
+			line = fmt.Sprintf("[%s] %s", t.name, line)
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
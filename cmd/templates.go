@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/spf13/cobra"
+)
+
+var templateVarArgs []string
+var templateDataFile string
+
+func init() {
+	templatesRenderCmd.Flags().StringArrayVar(&templateVarArgs, "var", nil, "Template variable in the form key=value. Can be repeated")
+	templatesRenderCmd.Flags().StringVar(&templateDataFile, "data", "", "Json file of template variables, use - to read from stdin")
+
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesRenderCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
+
+// Build template variables from a json data file (or stdin) overridden by key=value pairs
+func templateVars(cmd *cobra.Command, varArgs []string, dataFile string) (map[string]any, error) {
+	vars := map[string]any{}
+
+	if dataFile != "" {
+		var data []byte
+		var err error
+		if dataFile == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(dataFile)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &vars); err != nil {
+			return nil, fmt.Errorf("template data must be a json object: %w", err)
+		}
+	}
+
+	for _, v := range varArgs {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", v)
+		}
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+var templatesCmd = &cobra.Command{
+	Use:     "templates",
+	Aliases: []string{"template"},
+	Short:   "Preview message templates defined in the config file",
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates := config.GetConfig().Templates
+		if jsonOutput {
+			return printJSON(templates)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(templates)) {
+			fmt.Printf("%s\t%s\n", name, templates[name].Description)
+		}
+		return nil
+	},
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print the source of a template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := slackutils.GetTemplate(args[0])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(t)
+		}
+
+		fmt.Println(t.Text)
+		return nil
+	},
+}
+
+var templatesRenderCmd = &cobra.Command{
+	Use:   "render <name>",
+	Short: "Render a template without sending it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vars, err := templateVars(cmd, templateVarArgs, templateDataFile)
+		if err != nil {
+			return err
+		}
+
+		message, err := slackutils.RenderTemplate(args[0], vars)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(message)
+		return nil
+	},
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+// Unread messages are counted up to this many per conversation
+const unreadCountLimit = 100
+
+var unreadsCompact bool
+
+func init() {
+	unreadsCmd.Flags().BoolVarP(&unreadsCompact, "compact", "c", false, "Print a single summary line for status bars")
+	rootCmd.AddCommand(unreadsCmd)
+}
+
+type unreadConversation struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Unread   *int   `json:"unread"` // nil when the messages could not be counted
+	Mentions int    `json:"mentions"`
+}
+
+type unreadSummary struct {
+	Conversations  int `json:"conversations"`
+	Mentions       int `json:"mentions"`
+	ThreadMentions int `json:"thread_mentions"`
+}
+
+var unreadsCmd = &cobra.Command{
+	Use:   "unreads",
+	Short: "List conversations with unread messages and mentions",
+	Long:  fmt.Sprintf("Lists conversations with unread messages, most mentions first. Direct messages use the counts slack already tracks, channel messages are counted up to %d per channel. Counts that could not be fetched are shown as ?.", unreadCountLimit),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		counts, err := slackutils.GetClientCounts()
+		if err != nil {
+			return err
+		}
+
+		unread := []unreadConversation{}
+		lastRead := map[string]string{}
+		for kind, list := range map[string][]slackutils.ConversationCount{"channel": counts.Channels, "mpim": counts.MPIMs, "im": counts.IMs} {
+			for _, c := range list {
+				if !c.HasUnreads && c.MentionCount == 0 {
+					continue
+				}
+				conversation := unreadConversation{ID: c.ID, Type: kind, Mentions: c.MentionCount}
+				if n, ok := c.KnownUnreads(kind != "channel"); ok {
+					conversation.Unread = &n
+				} else {
+					lastRead[c.ID] = c.LastRead
+				}
+				unread = append(unread, conversation)
+			}
+		}
+
+		summary := unreadSummary{Conversations: len(unread), ThreadMentions: counts.Threads.MentionCount}
+		for _, c := range unread {
+			summary.Mentions += c.Mentions
+		}
+
+		if unreadsCompact {
+			if jsonOutput {
+				return printJSON(summary)
+			}
+			fmt.Printf("%d unread · %d mentions\n", summary.Conversations, summary.Mentions+summary.ThreadMentions)
+			return nil
+		}
+
+		if err := nameUnreadConversations(unread); err != nil {
+			return err
+		}
+
+		// Only channels need their history read, client.counts has the rest
+		runParallel(len(unread), 4, func(i int) {
+			if unread[i].Unread != nil {
+				return
+			}
+			n, err := slackutils.CountUnreadMessages(unread[i].ID, lastRead[unread[i].ID], unreadCountLimit)
+			if err != nil {
+				logrus.WithError(err).WithField("channel", unread[i].Name).Warn("could not count unread messages")
+				return
+			}
+			unread[i].Unread = &n
+		})
+
+		slices.SortFunc(unread, func(a, b unreadConversation) int {
+			return cmp.Or(cmp.Compare(b.Mentions, a.Mentions), cmp.Compare(b.unreadCount(), a.unreadCount()), cmp.Compare(a.Name, b.Name))
+		})
+
+		if jsonOutput {
+			return printJSON(unread)
+		}
+
+		for _, c := range unread {
+			count := "?"
+			if c.Unread != nil {
+				count = fmt.Sprint(*c.Unread)
+			}
+			if c.unreadCount() >= unreadCountLimit {
+				count += "+"
+			}
+			fmt.Printf("%-30s %4s unread %3d mentions\n", c.Name, count, c.Mentions)
+		}
+		if counts.Threads.HasUnreads {
+			fmt.Printf("%-30s %4s        %3d mentions\n", "Threads", "", counts.Threads.MentionCount)
+		}
+
+		return nil
+	},
+}
+
+// Unread messages for sorting, conversations that could not be counted sort last
+func (c unreadConversation) unreadCount() int {
+	if c.Unread == nil {
+		return -1
+	}
+	return *c.Unread
+}
+
+// Fill in display names, using #channel for channels and @user for direct messages
+func nameUnreadConversations(unread []unreadConversation) error {
+	channels, err := slackutils.GetAllConversations()
+	if err != nil {
+		return err
+	}
+
+	byID := map[string]string{}
+	userOf := map[string]string{}
+	for _, c := range channels {
+		byID[c.ID] = c.Name
+		userOf[c.ID] = c.User
+	}
+
+	users := slackutils.NewUserCache()
+	for i, c := range unread {
+		// client.userBoot does not always include direct messages
+		if c.Type == "im" && userOf[c.ID] == "" {
+			info, err := config.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: c.ID})
+			if err == nil {
+				userOf[c.ID] = info.User
+			}
+		}
+
+		switch {
+		case c.Type == "im" && userOf[c.ID] != "":
+			name, err := users.Resolve(userOf[c.ID])
+			if err != nil {
+				name = userOf[c.ID]
+			}
+			unread[i].Name = "@" + name
+		case byID[c.ID] != "" && c.Type == "channel":
+			unread[i].Name = "#" + byID[c.ID]
+		case byID[c.ID] != "":
+			unread[i].Name = byID[c.ID]
+		default:
+			unread[i].Name = c.ID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/graytonio/slack-cli/lib/slackutils"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var usersFilter string
+var usersIncludeDeleted bool
+var usersIncludeBots bool
+
+func init() {
+	usersCmd.Flags().StringVarP(&usersFilter, "filter", "f", "", "Only list users whose name, title or email contains the filter")
+	usersCmd.Flags().BoolVar(&usersIncludeDeleted, "deleted", false, "Include deactivated users")
+	usersCmd.Flags().BoolVar(&usersIncludeBots, "bots", false, "Include bot users")
+
+	rootCmd.AddCommand(userCmd)
+	rootCmd.AddCommand(usersCmd)
+}
+
+type userDetails struct {
+	User      *slack.User            `json:"user"`
+	Status    *slackutils.UserStatus `json:"status"`
+	DMChannel string                 `json:"dm_channel_id,omitempty"`
+}
+
+type userSummary struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Title    string `json:"title,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+var userCmd = &cobra.Command{
+	Use:     "user <@name|id|email>",
+	Aliases: []string{"whois"},
+	Short:   "Show a user's profile, local time, status and direct message channel",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, err := slackutils.ResolveUserID(args[0])
+		if err != nil {
+			return err
+		}
+
+		user, err := config.SlackClient.GetUserInfo(userID)
+		if err != nil {
+			return err
+		}
+
+		status, err := slackutils.GetUserStatus(userID)
+		if err != nil {
+			return err
+		}
+
+		details := userDetails{User: user, Status: status}
+
+		dm, _, _, err := config.SlackClient.OpenConversation(&slack.OpenConversationParameters{Users: []string{userID}})
+		if err != nil {
+			logrus.WithError(err).WithField("user", userID).Debug("could not open direct message channel")
+		} else {
+			details.DMChannel = dm.ID
+		}
+
+		if jsonOutput {
+			return printJSON(details)
+		}
+
+		fmt.Printf("User:     @%s (%s)\n", slackutils.UserDisplayName(user), user.ID)
+		fmt.Printf("Name:     %s\n", user.RealName)
+		if user.Profile.Title != "" {
+			fmt.Printf("Title:    %s\n", user.Profile.Title)
+		}
+		if user.Profile.Email != "" {
+			fmt.Printf("Email:    %s\n", user.Profile.Email)
+		}
+		if user.Profile.Phone != "" {
+			fmt.Printf("Phone:    %s\n", user.Profile.Phone)
+		}
+		if loc, err := time.LoadLocation(user.TZ); err == nil && user.TZ != "" {
+			fmt.Printf("Timezone: %s (local time %s)\n", user.TZ, time.Now().In(loc).Format("Mon 15:04 MST"))
+		}
+		printUserStatus(status)
+		if details.DMChannel != "" {
+			fmt.Printf("DM:       %s\n", details.DMChannel)
+		}
+
+		return nil
+	},
+}
+
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Search the workspace directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		users, err := slackutils.SearchUsers(usersFilter, usersIncludeDeleted, usersIncludeBots)
+		if err != nil {
+			return err
+		}
+
+		summaries := make([]userSummary, 0, len(users))
+		for _, u := range users {
+			summaries = append(summaries, userSummary{
+				ID:       u.ID,
+				Name:     slackutils.UserDisplayName(&u),
+				RealName: u.RealName,
+				Title:    u.Profile.Title,
+				Email:    u.Profile.Email,
+			})
+		}
+
+		if jsonOutput {
+			return printJSON(summaries)
+		}
+
+		for _, u := range summaries {
+			fmt.Printf("%s\t@%s\t%s\t%s\n", u.ID, u.Name, u.RealName, u.Title)
+		}
+		return nil
+	},
+}
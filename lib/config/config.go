@@ -19,8 +19,50 @@ import (
 var home, _ = os.UserHomeDir()
 
 type SmartSection struct {
-	SectionName  string `mapstructure:"section"`
-	ReExpression string `mapstructure:"re"`
+	SectionName  string       `mapstructure:"section"`
+	ReExpression string       `mapstructure:"re"`
+	Emoji        string       `mapstructure:"emoji"`
+	Before       string       `mapstructure:"before"`
+	Priority     int          `mapstructure:"priority"`
+	Match        *SectionRule `mapstructure:"match"`
+}
+
+// A condition a channel must meet to be sorted into a smart section. Every field
+// that is set must match, all/any/not combine nested rules
+type SectionRule struct {
+	Name            string        `mapstructure:"name" json:"name,omitempty"`
+	Topic           string        `mapstructure:"topic" json:"topic,omitempty"`
+	Purpose         string        `mapstructure:"purpose" json:"purpose,omitempty"`
+	Type            []string      `mapstructure:"type" json:"type,omitempty"`
+	Archived        *bool         `mapstructure:"archived" json:"archived,omitempty"`
+	MinMembers      int           `mapstructure:"min_members" json:"min_members,omitempty"`
+	MaxMembers      int           `mapstructure:"max_members" json:"max_members,omitempty"`
+	ActiveWithin    string        `mapstructure:"active_within" json:"active_within,omitempty"`
+	InactiveFor     string        `mapstructure:"inactive_for" json:"inactive_for,omitempty"`
+	MentionedWithin string        `mapstructure:"mentioned_within" json:"mentioned_within,omitempty"`
+	PostedWithin    string        `mapstructure:"posted_within" json:"posted_within,omitempty"`
+	All             []SectionRule `mapstructure:"all" json:"all,omitempty"`
+	Any             []SectionRule `mapstructure:"any" json:"any,omitempty"`
+	Not             *SectionRule  `mapstructure:"not" json:"not,omitempty"`
+}
+
+// Channels to leave, archive or mute with the cleanup command
+type CleanupPolicy struct {
+	Name   string      `mapstructure:"name" json:"name"`
+	Action string      `mapstructure:"action" json:"action"`
+	Match  SectionRule `mapstructure:"match" json:"match"`
+}
+
+// A notification setting applied in bulk with notify apply
+type NotificationPolicy struct {
+	Name    string      `mapstructure:"name" json:"name"`
+	Setting string      `mapstructure:"setting" json:"setting"`
+	Match   SectionRule `mapstructure:"match" json:"match"`
+}
+
+type MessageTemplate struct {
+	Description string `mapstructure:"description" json:"description"`
+	Text        string `mapstructure:"text" json:"text"`
 }
 
 type FavoriteChannel struct {
@@ -34,7 +76,11 @@ type Config struct {
 	SavedChannels     map[string]string                 `mapstructure:"channel_cache"`
 	SavedUsers        map[string]string                 `mapstructure:"users_cache"`
 	SmartSections     []SmartSection                    `mapstructure:"smart_sections"`
+	CleanupPolicies   []CleanupPolicy                   `mapstructure:"cleanup_policies"`
+	NotifyPolicies    []NotificationPolicy              `mapstructure:"notification_policies"`
 	FavoriteChannels  []FavoriteChannel                 `mapstructure:"favorite_channels"`
+	Templates         map[string]MessageTemplate        `mapstructure:"templates"`
+	ConfirmThreshold  int                               `mapstructure:"confirm_member_threshold"`
 }
 
 var config = Config{
@@ -42,7 +88,10 @@ var config = Config{
 	SavedChannels:    make(map[string]string),
 	SavedUsers:       make(map[string]string),
 	SmartSections:    []SmartSection{},
+	CleanupPolicies:  []CleanupPolicy{},
+	NotifyPolicies:   []NotificationPolicy{},
 	FavoriteChannels: []FavoriteChannel{},
+	Templates:        make(map[string]MessageTemplate),
 }
 
 var SlackClient *slack.Client
@@ -0,0 +1,156 @@
+package slackutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+)
+
+const maxBlocks = 50
+
+// Parse a Block Kit payload. Accepts either a bare array of blocks or an
+// object with a blocks field as produced by the Block Kit Builder
+func ParseBlocks(data []byte) (slack.Blocks, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		blocks := slack.Blocks{}
+		if err := json.Unmarshal(data, &blocks); err != nil {
+			return slack.Blocks{}, fmt.Errorf("invalid blocks json: %w", err)
+		}
+		return blocks, nil
+	}
+
+	payload := struct {
+		Blocks slack.Blocks `json:"blocks"`
+	}{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return slack.Blocks{}, fmt.Errorf("invalid blocks json: %w", err)
+	}
+	return payload.Blocks, nil
+}
+
+// ValidateBlocks checks blocks against the limits Slack enforces, returning every problem found
+func ValidateBlocks(blocks slack.Blocks) []error {
+	errs := []error{}
+
+	if len(blocks.BlockSet) == 0 {
+		errs = append(errs, fmt.Errorf("no blocks found"))
+	}
+	if len(blocks.BlockSet) > maxBlocks {
+		errs = append(errs, fmt.Errorf("%d blocks found, messages can have at most %d", len(blocks.BlockSet), maxBlocks))
+	}
+
+	blockIDs := map[string]int{}
+	for i, block := range blocks.BlockSet {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("block %d (%s): %s", i, block.BlockType(), fmt.Sprintf(format, args...)))
+		}
+
+		checkText := func(name string, text *slack.TextBlockObject, maxLen int) {
+			if text == nil || text.Text == "" {
+				fail("%s is required", name)
+				return
+			}
+			if err := text.Validate(); err != nil {
+				fail("%s %s", name, err)
+			}
+			if n := utf8.RuneCountInString(text.Text); n > maxLen {
+				fail("%s is %d characters, the maximum is %d", name, n, maxLen)
+			}
+		}
+
+		blockID := ""
+		switch b := block.(type) {
+		case *slack.SectionBlock:
+			blockID = b.BlockID
+			if b.Text == nil && len(b.Fields) == 0 {
+				fail("text or fields are required")
+			}
+			if b.Text != nil {
+				checkText("text", b.Text, 3000)
+			}
+			if len(b.Fields) > 10 {
+				fail("%d fields found, the maximum is 10", len(b.Fields))
+			}
+			for j, field := range b.Fields {
+				checkText(fmt.Sprintf("field %d", j), field, 2000)
+			}
+		case *slack.HeaderBlock:
+			blockID = b.BlockID
+			checkText("text", b.Text, 150)
+			if b.Text != nil && b.Text.Type != slack.PlainTextType {
+				fail("text must be plain_text")
+			}
+		case *slack.ImageBlock:
+			blockID = b.BlockID
+			if b.ImageURL == "" {
+				fail("image_url is required")
+			}
+			if b.AltText == "" {
+				fail("alt_text is required")
+			}
+		case *slack.ContextBlock:
+			blockID = b.BlockID
+			if n := len(b.ContextElements.Elements); n == 0 || n > 10 {
+				fail("must have between 1 and 10 elements, found %d", n)
+			}
+		case *slack.ActionBlock:
+			blockID = b.BlockID
+			if b.Elements == nil || len(b.Elements.ElementSet) == 0 || len(b.Elements.ElementSet) > 25 {
+				n := 0
+				if b.Elements != nil {
+					n = len(b.Elements.ElementSet)
+				}
+				fail("must have between 1 and 25 elements, found %d", n)
+			}
+		case *slack.DividerBlock:
+			blockID = b.BlockID
+		case *slack.RichTextBlock:
+			blockID = b.BlockID
+		case *slack.FileBlock:
+			blockID = b.BlockID
+		case *slack.InputBlock:
+			blockID = b.BlockID
+		default:
+			fail("unsupported block type")
+		}
+
+		if blockID != "" {
+			if len(blockID) > 255 {
+				fail("block_id is longer than 255 characters")
+			}
+			if first, ok := blockIDs[blockID]; ok {
+				fail("block_id %q is already used by block %d", blockID, first)
+			}
+			blockIDs[blockID] = i
+		}
+	}
+
+	return errs
+}
+
+// BlocksFallbackText builds plain notification text from the header and section text of blocks
+func BlocksFallbackText(blocks slack.Blocks) string {
+	lines := []string{}
+	for _, block := range blocks.BlockSet {
+		switch b := block.(type) {
+		case *slack.HeaderBlock:
+			if b.Text != nil {
+				lines = append(lines, b.Text.Text)
+			}
+		case *slack.SectionBlock:
+			if b.Text != nil {
+				lines = append(lines, b.Text.Text)
+			}
+			for _, field := range b.Fields {
+				lines = append(lines, field.Text)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,32 @@
+package slackutils
+
+import (
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/slack-go/slack"
+)
+
+// Get the ids of every member of a conversation
+func GetAllConversationMembers(channelID string) ([]string, error) {
+	members := []string{}
+	cursor := ""
+
+	for {
+		page, next, err := config.SlackClient.GetUsersInConversation(&slack.GetUsersInConversationParameters{
+			ChannelID: channelID,
+			Cursor:    cursor,
+			Limit:     1000,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, page...)
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	return members, nil
+}
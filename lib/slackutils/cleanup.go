@@ -0,0 +1,282 @@
+package slackutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+const (
+	CleanupLeave   = "leave"
+	CleanupArchive = "archive"
+	CleanupMute    = "mute"
+)
+
+var cleanupActions = []string{CleanupLeave, CleanupArchive, CleanupMute}
+
+// The action undoing each cleanup action
+var cleanupUndoActions = map[string]string{
+	CleanupLeave:   "join",
+	CleanupArchive: "unarchive",
+	CleanupMute:    "unmute",
+}
+
+var ErrNothingToUndo = errors.New("no cleanup run to undo")
+
+// A single change made to a channel by a cleanup policy
+type CleanupAction struct {
+	Policy      string `json:"policy"`
+	Action      string `json:"action"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	Error       string `json:"error,omitempty"`
+	// Undone is set once the action has been reversed so a failed undo can be retried
+	Undone bool `json:"undone,omitempty"`
+}
+
+// The actions taken by one cleanup run
+type CleanupRun struct {
+	ID       string          `json:"id"`
+	Time     time.Time       `json:"time"`
+	Actions  []CleanupAction `json:"actions"`
+	UndoneAt *time.Time      `json:"undone_at,omitempty"`
+}
+
+// Every cleanup run, oldest first, so runs can be undone later
+type CleanupLog struct {
+	Runs []CleanupRun `json:"runs"`
+}
+
+// Read the cleanup log from a file. A missing file is an empty log
+func LoadCleanupLog(path string) (*CleanupLog, error) {
+	log := &CleanupLog{Runs: []CleanupRun{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return log, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, log); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// Write the cleanup log to a file, creating its directory when needed
+func (l *CleanupLog) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Find a run by id, or the latest run that has not been undone when id is empty
+func (l *CleanupLog) Run(id string) (*CleanupRun, error) {
+	for i := len(l.Runs) - 1; i >= 0; i-- {
+		run := &l.Runs[i]
+		if id == "" && run.UndoneAt == nil {
+			return run, nil
+		}
+		if id != "" && run.ID == id {
+			return run, nil
+		}
+	}
+
+	if id == "" {
+		return nil, ErrNothingToUndo
+	}
+	return nil, fmt.Errorf("cleanup run %s not found", id)
+}
+
+// Work out which channels the policies apply to. Policies are checked in order and only the
+// first matching policy applies to a channel, even when its action is skipped. Archived channels,
+// direct messages for leave and archive, private channels for leave as they can not be rejoined,
+// and channels that are already muted are skipped
+func PlanCleanup(policies []config.CleanupPolicy) ([]CleanupAction, error) {
+	rules := make([]*Rule, len(policies))
+	mute := false
+	for i, p := range policies {
+		rule, err := compilePolicy(p.Name, p.Action, cleanupActions, p.Match)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+		mute = mute || p.Action == CleanupMute
+	}
+
+	channels, err := GetAllConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := map[string]ChannelNotificationPrefs{}
+	if mute {
+		if prefs, err = GetChannelNotificationPrefs(); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := NewRuleContext()
+	actions := []CleanupAction{}
+	for _, c := range channels {
+		if c.IsArchived {
+			continue
+		}
+
+		i, err := firstMatchingRule(ctx, rules, c)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || !cleanupApplies(policies[i].Action, c, prefs) {
+			continue
+		}
+
+		logrus.WithField("channel", c.Name).WithField("policy", policies[i].Name).Debug("cleanup policy matched")
+		actions = append(actions, CleanupAction{Policy: policies[i].Name, Action: policies[i].Action, ChannelID: c.ID, ChannelName: c.Name})
+	}
+
+	slices.SortFunc(actions, func(a, b CleanupAction) int {
+		return strings.Compare(a.ChannelName, b.ChannelName)
+	})
+
+	return actions, nil
+}
+
+func cleanupApplies(action string, c slack.Channel, prefs map[string]ChannelNotificationPrefs) bool {
+	switch action {
+	case CleanupLeave:
+		if channelIsType(c, "private") {
+			logrus.WithField("channel", c.Name).Warn("skipping private channel, leaving it could not be undone")
+		}
+		return channelIsType(c, "public")
+	case CleanupArchive:
+		return !c.IsIM && !c.IsMpIM
+	case CleanupMute:
+		return !prefs[c.ID].Muted
+	}
+	return false
+}
+
+// Apply cleanup actions and record them as a new run in the log. The log is written after
+// every action so an interrupted run can still be undone. Failed actions are kept in the run
+// with their error and the run continues
+func ExecuteCleanup(actions []CleanupAction, logPath string) (*CleanupRun, error) {
+	log, err := LoadCleanupLog(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The random suffix keeps ids unique when several runs start within the same second
+	now := time.Now()
+	id := fmt.Sprintf("%s-%04x", now.Format("20060102-150405"), rand.IntN(0x10000))
+	log.Runs = append(log.Runs, CleanupRun{ID: id, Time: now, Actions: []CleanupAction{}})
+	run := &log.Runs[len(log.Runs)-1]
+
+	for _, a := range actions {
+		if err := applyCleanupAction(a.Action, a.ChannelID); err != nil {
+			logrus.WithError(err).WithField("channel", a.ChannelName).Warnf("could not %s channel", a.Action)
+			a.Error = err.Error()
+		}
+
+		run.Actions = append(run.Actions, a)
+		if err := log.Save(logPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return run, nil
+}
+
+func applyCleanupAction(action string, channelID string) error {
+	switch action {
+	case CleanupLeave:
+		_, err := config.SlackClient.LeaveConversation(channelID)
+		return err
+	case CleanupArchive:
+		return config.SlackClient.ArchiveConversation(channelID)
+	case CleanupMute:
+		return SetChannelMuted(channelID, true)
+	}
+	return fmt.Errorf("invalid action %q", action)
+}
+
+// Reverse the actions of a cleanup run, the latest run that has not been undone when id is empty.
+// Left channels are joined again, archived channels unarchived and muted channels unmuted.
+// The run is only marked undone once every action has been reversed, so running undo again
+// retries the reversals that failed. The returned actions are the reversals, with the error of any that failed
+func UndoCleanup(id string, logPath string) (*CleanupRun, []CleanupAction, error) {
+	log, err := LoadCleanupLog(logPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	run, err := log.Run(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if run.UndoneAt != nil {
+		return nil, nil, fmt.Errorf("cleanup run %s was already undone at %s", run.ID, run.UndoneAt.Format(time.DateTime))
+	}
+
+	undone := []CleanupAction{}
+	failed := false
+	for i := len(run.Actions) - 1; i >= 0; i-- {
+		a := &run.Actions[i]
+		if a.Error != "" || a.Undone {
+			continue
+		}
+
+		undo := CleanupAction{Policy: a.Policy, Action: cleanupUndoActions[a.Action], ChannelID: a.ChannelID, ChannelName: a.ChannelName}
+		if err := undoCleanupAction(a.Action, a.ChannelID); err != nil {
+			logrus.WithError(err).WithField("channel", a.ChannelName).Warnf("could not %s channel", undo.Action)
+			undo.Error = err.Error()
+			failed = true
+		} else {
+			a.Undone = true
+		}
+		undone = append(undone, undo)
+	}
+
+	if !failed {
+		now := time.Now()
+		run.UndoneAt = &now
+	}
+	if err := log.Save(logPath); err != nil {
+		return nil, nil, err
+	}
+
+	return run, undone, nil
+}
+
+func undoCleanupAction(action string, channelID string) error {
+	switch action {
+	case CleanupLeave:
+		_, _, _, err := config.SlackClient.JoinConversation(channelID)
+		return err
+	case CleanupArchive:
+		return config.SlackClient.UnArchiveConversation(channelID)
+	case CleanupMute:
+		return SetChannelMuted(channelID, false)
+	}
+	return fmt.Errorf("invalid action %q", action)
+}
@@ -0,0 +1,110 @@
+package slackutils
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/slack-go/slack"
+)
+
+// Read state of a single conversation as returned by client.counts
+type ConversationCount struct {
+	ID           string `json:"id"`
+	LastRead     string `json:"last_read"`
+	Latest       string `json:"latest"`
+	MentionCount int    `json:"mention_count"`
+	HasUnreads   bool   `json:"has_unreads"`
+}
+
+type ClientCountsResponse struct {
+	OK       bool                `json:"ok"`
+	Error    string              `json:"error"`
+	Channels []ConversationCount `json:"channels"`
+	MPIMs    []ConversationCount `json:"mpims"`
+	IMs      []ConversationCount `json:"ims"`
+	Threads  struct {
+		HasUnreads   bool `json:"has_unreads"`
+		MentionCount int  `json:"mention_count"`
+	} `json:"threads"`
+}
+
+// Get the unread state and mention counts of every conversation the user is a member of
+func GetClientCounts() (*ClientCountsResponse, error) {
+	body, _, err := RawSlackRequestFormData("POST", "client.counts", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ClientCountsResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.OK {
+		return nil, errors.New(response.Error)
+	}
+
+	return &response, nil
+}
+
+// Number of unread messages client.counts already knows without reading the history. Every
+// message in a direct message counts as a mention, and a conversation whose latest message was
+// read has none. ok is false when the messages have to be counted with CountUnreadMessages
+func (c ConversationCount) KnownUnreads(direct bool) (n int, ok bool) {
+	switch {
+	case !c.HasUnreads:
+		return 0, true
+	case c.Latest != "" && c.LastRead != "" && !ParseTimestamp(c.Latest).After(ParseTimestamp(c.LastRead)):
+		return 0, true
+	case direct:
+		return c.MentionCount, true
+	}
+	return 0, false
+}
+
+// Count the messages in a conversation after the last read timestamp, stopping at limit.
+// Rate limited requests are retried after the delay slack asks for
+func CountUnreadMessages(channelID string, lastRead string, limit int) (int, error) {
+	var resp *slack.GetConversationHistoryResponse
+	err := retryRateLimited(func() (err error) {
+		resp, err = config.SlackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
+			ChannelID: channelID,
+			Oldest:    lastRead,
+			Limit:     limit,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(resp.Messages), nil
+}
+
+// Search for messages mentioning a user posted after a time, newest first
+func GetMentionsSince(userID string, since time.Time, limit int) ([]slack.SearchMessage, error) {
+	return SearchMessagesSince("<@"+userID+">", since, limit)
+}
+
+// Search for messages matching a query posted after a time, newest first
+func SearchMessagesSince(query string, since time.Time, limit int) ([]slack.SearchMessage, error) {
+	// The after: modifier is exclusive and only has day precision so search from the day before and filter
+	query += " after:" + since.AddDate(0, 0, -1).Format("2006-01-02")
+
+	matches, err := SearchMessages(query, "timestamp", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	found := []slack.SearchMessage{}
+	for _, m := range matches {
+		if !ParseTimestamp(m.Timestamp).Before(since.Truncate(time.Second)) {
+			found = append(found, m)
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,189 @@
+package slackutils
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/kyokomi/emoji/v2"
+)
+
+var shortcodeRe = regexp.MustCompile(`:[a-zA-Z0-9_+-]+:`)
+
+// EmojiCache resolves Slack :shortcode: syntax to Unicode characters.
+// Standard emoji are resolved via a static mapping library; custom workspace
+// emoji are resolved via the Slack API.
+type EmojiCache struct {
+	mu       sync.RWMutex
+	custom   map[string]string // workspace emoji from Slack API
+	fallback func(name string) string
+}
+
+// NewEmojiCache creates an emoji cache. Custom emoji without a Unicode form
+// are passed to fallback for rendering; a nil fallback leaves them as :name:.
+func NewEmojiCache(fallback func(name string) string) *EmojiCache {
+	if fallback == nil {
+		fallback = func(name string) string { return ":" + name + ":" }
+	}
+	return &EmojiCache{fallback: fallback}
+}
+
+// SetCustom stores the workspace emoji map from Slack's emoji.list API.
+func (c *EmojiCache) SetCustom(emojis map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.custom = emojis
+}
+
+// LoadCustom fetches the workspace emoji from Slack's emoji.list API.
+func (c *EmojiCache) LoadCustom() error {
+	emojis, err := config.SlackClient.GetEmoji()
+	if err != nil {
+		return err
+	}
+	c.SetCustom(emojis)
+	return nil
+}
+
+// Has reports whether name (without colons) is a standard or custom emoji.
+// Skin tone modifiers such as "thumbsup::skin-tone-2" are ignored.
+func (c *EmojiCache) Has(name string) bool {
+	name, _, _ = strings.Cut(name, "::")
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.custom[name]; ok {
+		return true
+	}
+	_, ok := emoji.CodeMap()[":"+name+":"]
+	return ok
+}
+
+// Suggest returns up to n known emoji names that are close to name, for
+// helping with typos. Closer matches come first.
+func (c *EmojiCache) Suggest(name string, n int) []string {
+	name, _, _ = strings.Cut(name, "::")
+
+	c.mu.RLock()
+	candidates := make([]string, 0, len(c.custom))
+	for k := range c.custom {
+		candidates = append(candidates, k)
+	}
+	c.mu.RUnlock()
+	for k := range emoji.CodeMap() {
+		candidates = append(candidates, strings.Trim(k, ":"))
+	}
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+
+	maxDistance := max(2, len(name)/3)
+	suggestions := []suggestion{}
+	for _, candidate := range candidates {
+		d := editDistance(name, candidate)
+		if strings.Contains(candidate, name) {
+			d = min(d, 1)
+		}
+		if d <= maxDistance {
+			suggestions = append(suggestions, suggestion{name: candidate, distance: d})
+		}
+	}
+
+	slices.SortFunc(suggestions, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	names := []string{}
+	for _, s := range suggestions {
+		if len(names) == n {
+			break
+		}
+		if !slices.Contains(names, s.name) {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
+
+// editDistance computes the levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+// Replace converts :shortcode: sequences in text to their Unicode equivalents.
+// Resolution order: custom alias → custom URL (fallback) → standard library → leave as-is.
+func (c *EmojiCache) Replace(text string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return shortcodeRe.ReplaceAllStringFunc(text, func(match string) string {
+		name := match[1 : len(match)-1] // strip surrounding colons
+
+		// Check custom workspace emoji
+		if c.custom != nil {
+			if val, ok := c.custom[name]; ok {
+				return c.resolveCustom(val, name)
+			}
+		}
+
+		// Try standard emoji library
+		candidate := emoji.Sprint(match)
+		if candidate != match {
+			return strings.TrimSpace(candidate)
+		}
+
+		// Leave as-is if unrecognized
+		return match
+	})
+}
+
+// resolveCustom handles a custom emoji value. Aliases start with "alias:"
+// and point to another emoji name. URL values have no Unicode form so we
+// render the fallback.
+func (c *EmojiCache) resolveCustom(val, name string) string {
+	if target, ok := strings.CutPrefix(val, "alias:"); ok {
+
+		// Check if alias points to another custom emoji
+		if c.custom != nil {
+			if v2, ok := c.custom[target]; ok {
+				return c.resolveCustom(v2, target)
+			}
+		}
+
+		// Try standard emoji library for the alias target
+		code := ":" + target + ":"
+		candidate := emoji.Sprint(code)
+		if candidate != code {
+			return strings.TrimSpace(candidate)
+		}
+	}
+
+	// Image URL or unresolvable alias — fallback
+	return c.fallback(name)
+}
@@ -0,0 +1,118 @@
+package slackutils
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var markupRe = regexp.MustCompile(`<([^<>]+)>`)
+
+var entityReplacer = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// TextFormatter converts Slack message markup (mentions, channel references,
+// links and emoji shortcodes) into plain text or another markup language.
+type TextFormatter struct {
+	Users *UserCache
+	Emoji *EmojiCache
+	// Escape is applied to every piece of literal text. Defaults to no escaping
+	Escape func(text string) string
+	// Link renders a hyperlink. Defaults to the label followed by the url
+	Link func(url string, label string) string
+}
+
+// Format renders a Slack message text using the formatter's rules
+func (f TextFormatter) Format(text string) string {
+	escape := f.Escape
+	if escape == nil {
+		escape = func(text string) string { return text }
+	}
+
+	literal := func(text string) string {
+		text = entityReplacer.Replace(text)
+		if f.Emoji != nil {
+			text = f.Emoji.Replace(text)
+		}
+		return escape(text)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range markupRe.FindAllStringSubmatchIndex(text, -1) {
+		sb.WriteString(literal(text[last:loc[0]]))
+		sb.WriteString(f.formatMarkup(text[loc[2]:loc[3]], literal))
+		last = loc[1]
+	}
+	sb.WriteString(literal(text[last:]))
+
+	return sb.String()
+}
+
+func (f TextFormatter) formatMarkup(markup string, literal func(string) string) string {
+	target, label, _ := strings.Cut(markup, "|")
+
+	switch {
+	case strings.HasPrefix(target, "@"):
+		if label != "" {
+			return literal("@" + label)
+		}
+		id := strings.TrimPrefix(target, "@")
+		if f.Users != nil {
+			if name, err := f.Users.Resolve(id); err == nil {
+				return literal("@" + name)
+			}
+		}
+		return literal("@" + id)
+	case strings.HasPrefix(target, "#"):
+		if label == "" {
+			label = strings.TrimPrefix(target, "#")
+		}
+		return literal("#" + label)
+	case strings.HasPrefix(target, "!"):
+		if label != "" {
+			return literal(label)
+		}
+		return literal("@" + strings.TrimPrefix(target, "!"))
+	}
+
+	url := entityReplacer.Replace(target)
+	if label == "" {
+		label = url
+	} else {
+		label = entityReplacer.Replace(label)
+	}
+
+	if f.Link != nil {
+		return f.Link(url, label)
+	}
+	if label == url {
+		return literal(url)
+	}
+	return literal(label + " (" + url + ")")
+}
+
+// ParseTimestamp converts a Slack message timestamp ("epoch.seq") into a time
+func ParseTimestamp(ts string) time.Time {
+	secs, _, _ := strings.Cut(ts, ".")
+	epoch, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(epoch, 0)
+}
+
+var broadcastRe = regexp.MustCompile(`<!(channel|here|everyone)(?:\|[^>]*)?>|(?:^|[^\w<])@(channel|here|everyone)\b`)
+
+// BroadcastMentions returns the broadcast mentions (channel, here, everyone) found in a message text
+func BroadcastMentions(text string) []string {
+	found := []string{}
+	for _, m := range broadcastRe.FindAllStringSubmatch(text, -1) {
+		name := m[1] + m[2]
+		if !slices.Contains(found, name) {
+			found = append(found, name)
+		}
+	}
+	return found
+}
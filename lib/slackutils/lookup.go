@@ -3,14 +3,23 @@ package slackutils
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"path"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/graytonio/slack-cli/lib/config"
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 )
 
+var (
+	channelIDRe = regexp.MustCompile(`^[CGD][A-Z0-9]{6,}$`)
+	userIDRe    = regexp.MustCompile(`^[UW][A-Z0-9]{6,}$`)
+)
+
 func ParseChannelTarget(arg string) (string, error) {
 	if strings.HasPrefix(arg, "@") {
 		logrus.WithField("target", arg).WithField("type", "user").Debug("looking up user")
@@ -44,6 +53,118 @@ func ParseChannelTarget(arg string) (string, error) {
 	}
 }
 
+// Resolve a channel target to a conversation id. Unlike ParseChannelTarget users are
+// resolved to the id of the direct message channel with them which is required by
+// apis operating on existing messages
+func ResolveConversationID(arg string) (string, error) {
+	target, err := ParseChannelTarget(arg)
+	if err != nil {
+		return "", err
+	}
+
+	if !userIDRe.MatchString(target) {
+		return target, nil
+	}
+
+	return openDirectMessage(target)
+}
+
+func openDirectMessage(userID string) (string, error) {
+	logrus.WithField("user", userID).Debug("opening direct message channel")
+	c, _, _, err := config.SlackClient.OpenConversation(&slack.OpenConversationParameters{
+		Users: []string{userID},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return c.ID, nil
+}
+
+// Resolve a channel argument to a channel id. Channel names the user has not joined
+// are not returned by client.userBoot so public and archived channels are searched as well.
+// Users are rejected as channel apis would be given a user id
+func ResolveChannelID(arg string) (string, error) {
+	if strings.HasPrefix(arg, "@") || userIDRe.MatchString(arg) {
+		return "", fmt.Errorf("%s is a user, expected a channel", arg)
+	}
+
+	target, err := ParseChannelTarget(arg)
+	if err == nil || !errors.Is(err, ErrChannelNotFound) {
+		return target, err
+	}
+
+	logrus.WithField("target", arg).Debug("channel not joined, searching all channels")
+	c, err := GetPublicChannelByName(strings.TrimPrefix(arg, "#"))
+	if err != nil {
+		return "", err
+	}
+
+	return c.ID, nil
+}
+
+// Lookup any public or archived channel by name, including channels the user is not a member of
+func GetPublicChannelByName(name string) (*slack.Channel, error) {
+	cursor := ""
+	for {
+		channels, next, err := config.SlackClient.GetConversations(&slack.GetConversationsParameters{
+			Cursor: cursor,
+			Limit:  1000,
+			Types:  []string{"public_channel", "private_channel"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range channels {
+			if c.Name == name {
+				return &c, nil
+			}
+		}
+
+		if next == "" {
+			return nil, ErrChannelNotFound
+		}
+		cursor = next
+	}
+}
+
+// Resolve a user argument (@name, a saved user, an email address or a user id) to a user id
+func ResolveUserID(arg string) (string, error) {
+	if userIDRe.MatchString(arg) {
+		return arg, nil
+	}
+
+	if !strings.HasPrefix(arg, "@") && strings.Contains(arg, "@") {
+		logrus.WithField("target", arg).WithField("type", "email").Debug("looking up user")
+		u, err := config.SlackClient.GetUserByEmail(arg)
+		if err != nil {
+			return "", err
+		}
+		return u.ID, nil
+	}
+
+	target, err := ParseChannelTarget("@" + strings.TrimPrefix(arg, "@"))
+	if err != nil {
+		return "", err
+	}
+
+	if !userIDRe.MatchString(target) {
+		return "", ErrUserNotFound
+	}
+
+	return target, nil
+}
+
+// Get the user id of the authenticated user
+func GetCurrentUserID() (string, error) {
+	auth, err := config.SlackClient.AuthTest()
+	if err != nil {
+		return "", err
+	}
+	return auth.UserID, nil
+}
+
 // Get the Definition of a channel section by name
 func GetSectionByName(name string) (*ChannelSection, error) {
 	sections, err := GetChannelSections()
@@ -86,6 +207,41 @@ func GetSectionOfChannelName(channel string) (*ChannelSection, error) {
 	return nil, ErrChannelSectionNotFound
 }
 
+// Select the channels matching any of the patterns. A pattern is a channel id, a channel
+// name with or without a leading # or a glob such as "inc-*". Every pattern has to match
+// at least one channel
+func MatchChannels(channels []slack.Channel, patterns []string) ([]slack.Channel, error) {
+	matched := []slack.Channel{}
+	seen := map[string]bool{}
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(pattern, "#")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+
+		found := false
+		for _, c := range channels {
+			ok, _ := path.Match(pattern, c.Name)
+			if !ok && c.ID != pattern {
+				continue
+			}
+
+			found = true
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				matched = append(matched, c)
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%s: %w", pattern, ErrChannelNotFound)
+		}
+	}
+
+	return matched, nil
+}
+
 // Lookup channel object by name
 func GetChannelByName(name string) (channel *slack.Channel, err error) {
 	channels, err := GetAllConversations()
@@ -103,22 +259,124 @@ func GetChannelByName(name string) (channel *slack.Channel, err error) {
 }
 
 // FIXME Too slow (cache search users)
-// Lookup a user by their display name
+// Lookup a user by their display name, falling back to their username
 func GetUserByName(name string) (*slack.User, error) {
 	users, err := config.SlackClient.GetUsers()
 	if err != nil {
 		return nil, err
 	}
 
+	return findUserByName(users, name)
+}
+
+func findUserByName(users []slack.User, name string) (*slack.User, error) {
 	for _, u := range users {
 		if u.Profile.DisplayName == name {
 			return &u, nil
 		}
 	}
 
+	for _, u := range users {
+		if u.Name == name {
+			return &u, nil
+		}
+	}
+
 	return nil, ErrUserNotFound
 }
 
+// Resolves many channel targets like ResolveConversationID from a single copy of the
+// conversations and users, instead of fetching them again for every target. Safe for concurrent use
+type ConversationResolver struct {
+	mu       sync.Mutex
+	channels []slack.Channel
+	users    []slack.User
+}
+
+func NewConversationResolver() *ConversationResolver {
+	return &ConversationResolver{}
+}
+
+func (r *ConversationResolver) getChannels() ([]slack.Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.channels == nil {
+		channels, err := GetAllConversations()
+		if err != nil {
+			return nil, err
+		}
+		r.channels = channels
+	}
+	return r.channels, nil
+}
+
+func (r *ConversationResolver) getUsers() ([]slack.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.users == nil {
+		users, err := config.SlackClient.GetUsers()
+		if err != nil {
+			return nil, err
+		}
+		r.users = users
+	}
+	return r.users, nil
+}
+
+// Resolve a channel target to a conversation id and the channel name when it is known.
+// Users are resolved to the id of the direct message channel with them
+func (r *ConversationResolver) Resolve(arg string) (string, string, error) {
+	if strings.HasPrefix(arg, "@") {
+		user := strings.TrimPrefix(arg, "@")
+		userID, ok := config.GetConfig().SavedUsers[user]
+		if !ok {
+			users, err := r.getUsers()
+			if err != nil {
+				return "", "", err
+			}
+			u, err := findUserByName(users, user)
+			if err != nil {
+				return "", "", err
+			}
+			userID = u.ID
+		}
+
+		// Saved users may point at a direct message channel instead of a user
+		if !userIDRe.MatchString(userID) {
+			return userID, "", nil
+		}
+
+		channelID, err := openDirectMessage(userID)
+		return channelID, "", err
+	}
+
+	if userIDRe.MatchString(arg) {
+		channelID, err := openDirectMessage(arg)
+		return channelID, "", err
+	}
+
+	channels, err := r.getChannels()
+	if err != nil {
+		return "", "", err
+	}
+
+	name := strings.TrimPrefix(arg, "#")
+	for _, c := range channels {
+		if (strings.HasPrefix(arg, "#") && c.Name == name) || (!strings.HasPrefix(arg, "#") && c.ID == arg) {
+			return c.ID, c.Name, nil
+		}
+	}
+
+	if strings.HasPrefix(arg, "#") {
+		return "", "", ErrChannelNotFound
+	}
+
+	// Ids of conversations missing from client.userBoot are passed through as they are
+	return arg, "", nil
+}
+
 type userBootResponseData struct {
 	Channels []slack.Channel `json:"channels"`
 }
@@ -0,0 +1,436 @@
+package slackutils
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+)
+
+var (
+	mdFenceRe      = regexp.MustCompile("^\\s*(```|~~~)")
+	mdHeadingRe    = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdRuleRe       = regexp.MustCompile(`^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdBulletRe     = regexp.MustCompile(`^(\s*)[-*+]\s+(\[( |x|X)\]\s+)?`)
+	mdOrderedRe    = regexp.MustCompile(`^(\s*)\d+[.)]\s+`)
+	mdInlineCodeRe = regexp.MustCompile("`[^`]*`")
+	mdImageRe      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdLinkRe       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdAutoLinkRe   = regexp.MustCompile(`&lt;(https?://\S+?)&gt;`)
+	mdRawLinkRe    = regexp.MustCompile(`<(https?://[^\s>]+)>`)
+	mdBoldRe       = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	mdItalicRe     = regexp.MustCompile(`(^|[^\w*])\*([^\s*](?:[^*]*?[^\s*])?)\*([^\w*]|$)`)
+	mdStrikeRe     = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	// Underscores are already italic in mrkdwn so this is only needed for rich text
+	mdUnderscoreRe = regexp.MustCompile(`(^|[^\w_])_([^\s_](?:[^_]*?[^\s_])?)_([^\w_]|$)`)
+)
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// MarkdownToMrkdwn converts CommonMark style markdown into Slack's mrkdwn
+// format. Headings become bold lines, links use Slack's <url|label> syntax,
+// bullets are rendered with • and emphasis markers are rewritten. Code spans
+// and fenced code blocks are passed through with only Slack's required escaping.
+func MarkdownToMrkdwn(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	inFence := false
+	fence := ""
+	for _, line := range lines {
+		if m := mdFenceRe.FindStringSubmatch(line); m != nil {
+			if !inFence {
+				inFence = true
+				fence = m[1]
+				out = append(out, "```")
+				continue
+			}
+			if m[1] == fence {
+				inFence = false
+				out = append(out, "```")
+				continue
+			}
+		}
+
+		if inFence {
+			out = append(out, mrkdwnEscaper.Replace(line))
+			continue
+		}
+
+		out = append(out, convertMarkdownLine(line))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func convertMarkdownLine(line string) string {
+	if mdRuleRe.MatchString(line) {
+		return "──────────"
+	}
+
+	if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+		return "*" + convertMarkdownInline(m[1], false) + "*"
+	}
+
+	// Blockquotes keep their marker, the rest of the line is converted
+	quote := ""
+	for {
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		quote += "> "
+		line = strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+	}
+
+	if m := mdBulletRe.FindStringSubmatch(line); m != nil {
+		bullet := "• "
+		switch m[3] {
+		case " ":
+			bullet = "☐ "
+		case "x", "X":
+			bullet = "☑ "
+		}
+		return quote + m[1] + bullet + convertMarkdownInline(line[len(m[0]):], true)
+	}
+
+	return quote + convertMarkdownInline(line, true)
+}
+
+// Convert inline markdown, leaving the contents of code spans untouched
+func convertMarkdownInline(text string, emphasis bool) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range mdInlineCodeRe.FindAllStringIndex(text, -1) {
+		sb.WriteString(convertMarkdownSpan(text[last:loc[0]], emphasis))
+		sb.WriteString(mrkdwnEscaper.Replace(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	sb.WriteString(convertMarkdownSpan(text[last:], emphasis))
+	return sb.String()
+}
+
+func convertMarkdownSpan(text string, emphasis bool) string {
+	text = mrkdwnEscaper.Replace(text)
+
+	text = mdImageRe.ReplaceAllString(text, "<$2|$1>")
+	text = mdLinkRe.ReplaceAllString(text, "<$2|$1>")
+	text = mdAutoLinkRe.ReplaceAllString(text, "<$1>")
+	text = mdStrikeRe.ReplaceAllString(text, "~$1~")
+
+	if !emphasis {
+		// Headings are already bold so only strip bold markers
+		return mdBoldRe.ReplaceAllString(text, "$1$2")
+	}
+
+	// Italic must be rewritten before bold is turned into single asterisks
+	text = mdItalicRe.ReplaceAllString(text, "${1}_${2}_${3}")
+	text = mdBoldRe.ReplaceAllString(text, "*$1$2*")
+
+	return text
+}
+
+// Header blocks are limited to this many characters, longer headings become bold sections
+const maxHeaderLength = 150
+
+// Section text is limited to this many characters, longer paragraphs are split
+const maxSectionLength = 3000
+
+// A rich_text_list element, which slack-go does not provide
+type richTextList struct {
+	Type     slack.RichTextElementType `json:"type"`
+	Style    string                    `json:"style"`
+	Indent   int                       `json:"indent"`
+	Elements []slack.RichTextElement   `json:"elements"`
+}
+
+func (l richTextList) RichTextElementType() slack.RichTextElementType {
+	return l.Type
+}
+
+// A rich_text_preformatted element, which slack-go does not provide
+type richTextPreformatted struct {
+	Type     slack.RichTextElementType      `json:"type"`
+	Elements []slack.RichTextSectionElement `json:"elements"`
+}
+
+func (p richTextPreformatted) RichTextElementType() slack.RichTextElementType {
+	return p.Type
+}
+
+type markdownListItem struct {
+	indent  int
+	ordered bool
+	text    string
+}
+
+// MarkdownToBlocks converts CommonMark style markdown into blocks so its structure
+// survives in Slack. Headings become header blocks, bullet and numbered lists become
+// rich text lists keeping their nesting, fenced code becomes preformatted rich text and
+// rules become dividers. Everything else is converted with MarkdownToMrkdwn into sections.
+func MarkdownToBlocks(markdown string) slack.Blocks {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	blocks := []slack.Block{}
+
+	paragraph := []string{}
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, markdownSections(paragraph)...)
+			paragraph = []string{}
+		}
+	}
+
+	items := []markdownListItem{}
+	flushList := func() {
+		if len(items) > 0 {
+			blocks = append(blocks, markdownList(items))
+			items = []markdownListItem{}
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := mdFenceRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			flushList()
+
+			code := []string{}
+			for i++; i < len(lines); i++ {
+				if f := mdFenceRe.FindStringSubmatch(lines[i]); f != nil && f[1] == m[1] {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			if text := strings.Join(code, "\n"); strings.TrimSpace(text) != "" {
+				blocks = append(blocks, slack.NewRichTextBlock("", richTextPreformatted{
+					Type:     slack.RTEPreformatted,
+					Elements: []slack.RichTextSectionElement{slack.NewRichTextSectionTextElement(text, nil)},
+				}))
+			}
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			flushList()
+			continue
+		}
+
+		if mdRuleRe.MatchString(line) {
+			flushParagraph()
+			flushList()
+			blocks = append(blocks, slack.NewDividerBlock())
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			flushList()
+			blocks = append(blocks, markdownHeading(m[1]))
+			continue
+		}
+
+		if m := mdBulletRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			text := line[len(m[0]):]
+			switch m[3] {
+			case " ":
+				text = "☐ " + text
+			case "x", "X":
+				text = "☑ " + text
+			}
+			items = append(items, markdownListItem{indent: indentWidth(m[1]), text: text})
+			continue
+		}
+
+		if m := mdOrderedRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			items = append(items, markdownListItem{indent: indentWidth(m[1]), ordered: true, text: line[len(m[0]):]})
+			continue
+		}
+
+		flushList()
+		paragraph = append(paragraph, line)
+	}
+
+	flushParagraph()
+	flushList()
+
+	return slack.Blocks{BlockSet: blocks}
+}
+
+// Width of leading whitespace with tabs counted as four spaces
+func indentWidth(space string) int {
+	return len(strings.ReplaceAll(space, "\t", "    "))
+}
+
+// A header block for a heading, or a bold section when it is too long for a header
+func markdownHeading(text string) slack.Block {
+	plain := richTextPlain(richTextInline(text, slack.RichTextSectionTextStyle{}))
+	if utf8.RuneCountInString(plain) > maxHeaderLength {
+		return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*"+convertMarkdownInline(text, false)+"*", false, false), nil, nil)
+	}
+	return slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, plain, false, false))
+}
+
+// Mrkdwn sections for a paragraph, split on line boundaries to stay within the section limit
+func markdownSections(lines []string) []slack.Block {
+	sections := []slack.Block{}
+	text := ""
+	for _, line := range lines {
+		converted := convertMarkdownLine(line)
+		if text != "" && utf8.RuneCountInString(text)+1+utf8.RuneCountInString(converted) > maxSectionLength {
+			sections = append(sections, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil))
+			text = ""
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += converted
+	}
+	return append(sections, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil))
+}
+
+// A rich text block holding a list. Nesting levels are worked out from the indentation of
+// the items and each run of items with the same level and style becomes one list element
+func markdownList(items []markdownListItem) slack.Block {
+	elements := []slack.RichTextElement{}
+	widths := []int{}
+	var current *richTextList
+	for _, item := range items {
+		for len(widths) > 0 && widths[len(widths)-1] > item.indent {
+			widths = widths[:len(widths)-1]
+		}
+		if len(widths) == 0 || widths[len(widths)-1] < item.indent {
+			widths = append(widths, item.indent)
+		}
+		level := len(widths) - 1
+
+		style := "bullet"
+		if item.ordered {
+			style = "ordered"
+		}
+
+		if current == nil || current.Indent != level || current.Style != style {
+			if current != nil {
+				elements = append(elements, *current)
+			}
+			current = &richTextList{Type: slack.RTEList, Style: style, Indent: level}
+		}
+		current.Elements = append(current.Elements, slack.NewRichTextSection(richTextInline(item.text, slack.RichTextSectionTextStyle{})...))
+	}
+	elements = append(elements, *current)
+
+	return slack.NewRichTextBlock("", elements...)
+}
+
+// Convert inline markdown into rich text elements. Code spans, links, bold, italic and
+// strikethrough are turned into styled elements, nested emphasis keeps the outer style
+func richTextInline(text string, style slack.RichTextSectionTextStyle) []slack.RichTextSectionElement {
+	elements := []slack.RichTextSectionElement{}
+	textElement := func(t string, s slack.RichTextSectionTextStyle) {
+		if t == "" {
+			return
+		}
+		var stylePtr *slack.RichTextSectionTextStyle
+		if s != (slack.RichTextSectionTextStyle{}) {
+			stylePtr = &s
+		}
+		elements = append(elements, slack.NewRichTextSectionTextElement(t, stylePtr))
+	}
+
+	for text != "" {
+		// Find the earliest inline element, the order of the list breaks ties
+		start, end := -1, -1
+		var emit func()
+		for _, candidate := range []struct {
+			re   *regexp.Regexp
+			emit func(m []string)
+			// Submatch holding the span when the match includes surrounding characters
+			group int
+		}{
+			{mdInlineCodeRe, func(m []string) {
+				s := style
+				s.Code = true
+				textElement(strings.Trim(m[0], "`"), s)
+			}, 0},
+			{mdImageRe, func(m []string) { elements = append(elements, slack.NewRichTextSectionLinkElement(m[2], m[1], nil)) }, 0},
+			{mdLinkRe, func(m []string) { elements = append(elements, slack.NewRichTextSectionLinkElement(m[2], m[1], nil)) }, 0},
+			{mdRawLinkRe, func(m []string) { elements = append(elements, slack.NewRichTextSectionLinkElement(m[1], "", nil)) }, 0},
+			{mdBoldRe, func(m []string) {
+				s := style
+				s.Bold = true
+				elements = append(elements, richTextInline(m[1]+m[2], s)...)
+			}, 0},
+			{mdStrikeRe, func(m []string) {
+				s := style
+				s.Strike = true
+				elements = append(elements, richTextInline(m[1], s)...)
+			}, 0},
+			{mdItalicRe, func(m []string) {
+				s := style
+				s.Italic = true
+				elements = append(elements, richTextInline(m[2], s)...)
+			}, 2},
+			{mdUnderscoreRe, func(m []string) {
+				s := style
+				s.Italic = true
+				elements = append(elements, richTextInline(m[2], s)...)
+			}, 2},
+		} {
+			loc := candidate.re.FindStringSubmatchIndex(text)
+			if loc == nil {
+				continue
+			}
+
+			matchStart, matchEnd := loc[0], loc[1]
+			if candidate.group > 0 {
+				// Keep the markers around the group but not the characters before and after them
+				matchStart, matchEnd = loc[2*candidate.group]-1, loc[2*candidate.group+1]+1
+			}
+			if start >= 0 && matchStart >= start {
+				continue
+			}
+
+			m := make([]string, len(loc)/2)
+			for i := range m {
+				if loc[2*i] >= 0 {
+					m[i] = text[loc[2*i]:loc[2*i+1]]
+				}
+			}
+			start, end = matchStart, matchEnd
+			emit = func() { candidate.emit(m) }
+		}
+
+		if start < 0 {
+			textElement(text, style)
+			break
+		}
+
+		textElement(text[:start], style)
+		emit()
+		text = text[end:]
+	}
+
+	return elements
+}
+
+// The plain text of rich text elements, using the label or url of links
+func richTextPlain(elements []slack.RichTextSectionElement) string {
+	var sb strings.Builder
+	for _, e := range elements {
+		switch e := e.(type) {
+		case *slack.RichTextSectionTextElement:
+			sb.WriteString(e.Text)
+		case *slack.RichTextSectionLinkElement:
+			if e.Text != "" {
+				sb.WriteString(e.Text)
+			} else {
+				sb.WriteString(e.URL)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,126 @@
+package slackutils
+
+import (
+	"slices"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// Get the most recent messages in a channel. Messages are returned newest first
+func GetRecentMessages(channelID string, limit int) ([]slack.Message, error) {
+	resp, err := config.SlackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID:          channelID,
+		Limit:              limit,
+		IncludeAllMetadata: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Messages, nil
+}
+
+// Get every message in a channel posted after the given timestamp, paging until oldest is
+// reached so bursts of messages are not cut off. Messages are returned newest first
+func GetMessagesSince(channelID string, oldest string) ([]slack.Message, error) {
+	messages := []slack.Message{}
+	cursor := ""
+
+	for {
+		resp, err := config.SlackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
+			ChannelID:          channelID,
+			Oldest:             oldest,
+			Limit:              100,
+			Cursor:             cursor,
+			IncludeAllMetadata: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, resp.Messages...)
+
+		cursor = resp.ResponseMetadata.Cursor
+		if !resp.HasMore || cursor == "" {
+			break
+		}
+		logrus.WithField("channel", channelID).WithField("count", len(messages)).Debug("fetching next page of new messages")
+	}
+
+	return messages, nil
+}
+
+// Get every message in a channel by walking the full history. Pages that are rate limited are
+// retried after the delay slack asks for. Messages are returned oldest first
+func GetFullHistory(channelID string) ([]slack.Message, error) {
+	messages := []slack.Message{}
+	cursor := ""
+
+	for {
+		var resp *slack.GetConversationHistoryResponse
+		err := retryRateLimited(func() (err error) {
+			resp, err = config.SlackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
+				ChannelID:          channelID,
+				Limit:              200,
+				Cursor:             cursor,
+				IncludeAllMetadata: true,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		logrus.WithField("channel", channelID).WithField("count", len(resp.Messages)).Debug("fetched history page")
+		messages = append(messages, resp.Messages...)
+
+		cursor = resp.ResponseMetadata.Cursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// Get every reply in a thread, not including the parent message. Pages that are rate limited
+// are retried after the delay slack asks for. Replies are returned oldest first
+func GetAllThreadReplies(channelID string, threadTS string) ([]slack.Message, error) {
+	replies := []slack.Message{}
+	cursor := ""
+
+	for {
+		var msgs []slack.Message
+		var hasMore bool
+		var nextCursor string
+		err := retryRateLimited(func() (err error) {
+			msgs, hasMore, nextCursor, err = config.SlackClient.GetConversationReplies(&slack.GetConversationRepliesParameters{
+				ChannelID: channelID,
+				Timestamp: threadTS,
+				Cursor:    cursor,
+				Limit:     200,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range msgs {
+			if m.Timestamp == threadTS {
+				continue
+			}
+			replies = append(replies, m)
+		}
+
+		if !hasMore || nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	return replies, nil
+}
@@ -0,0 +1,215 @@
+package slackutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	NotifyMute     = "mute"
+	NotifyUnmute   = "unmute"
+	NotifyMentions = "mentions"
+	NotifyAll      = "all"
+	NotifyNothing  = "nothing"
+)
+
+var NotifySettings = []string{NotifyMute, NotifyUnmute, NotifyMentions, NotifyAll, NotifyNothing}
+
+// The desktop and mobile notification level slack uses for each setting
+var notifyLevels = map[string]string{
+	NotifyMentions: "mention",
+	NotifyAll:      "everything",
+	NotifyNothing:  "nothing",
+}
+
+// A notification setting applied to a channel by a policy
+type NotifyChange struct {
+	Policy      string `json:"policy"`
+	Setting     string `json:"setting"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Notification settings of a single conversation
+type ChannelNotificationPrefs struct {
+	Muted   bool   `json:"muted"`
+	Desktop string `json:"desktop,omitempty"`
+	Mobile  string `json:"mobile,omitempty"`
+}
+
+type userPrefsResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	Prefs struct {
+		// A json encoded string holding the global and per channel notification settings
+		AllNotificationsPrefs string `json:"all_notifications_prefs"`
+	} `json:"prefs"`
+}
+
+// Get the notification settings of every conversation that differs from the defaults, keyed by channel id
+func GetChannelNotificationPrefs() (map[string]ChannelNotificationPrefs, error) {
+	body, _, err := RawSlackRequestFormData("POST", "users.prefs.get", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	response := userPrefsResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.OK {
+		return nil, errors.New(response.Error)
+	}
+
+	prefs := struct {
+		Channels map[string]ChannelNotificationPrefs `json:"channels"`
+	}{}
+	if response.Prefs.AllNotificationsPrefs != "" {
+		if err := json.Unmarshal([]byte(response.Prefs.AllNotificationsPrefs), &prefs); err != nil {
+			return nil, err
+		}
+	}
+	if prefs.Channels == nil {
+		prefs.Channels = make(map[string]ChannelNotificationPrefs)
+	}
+
+	return prefs.Channels, nil
+}
+
+// Change a single notification setting of a conversation
+func setChannelNotificationPref(channelID string, name string, value string) error {
+	body, _, err := RawSlackRequestFormData("POST", "users.prefs.setNotifications", map[string]string{
+		"channel_id": channelID,
+		"name":       name,
+		"value":      value,
+	})
+	if err != nil {
+		return err
+	}
+
+	response := struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return err
+	}
+
+	if !response.OK {
+		return errors.New(response.Error)
+	}
+
+	return nil
+}
+
+// Mute or unmute a conversation
+func SetChannelMuted(channelID string, muted bool) error {
+	return setChannelNotificationPref(channelID, "muted", strconv.FormatBool(muted))
+}
+
+// Apply a notification setting to a conversation. mute and unmute keep the notification
+// level, the other settings change the level on both desktop and mobile
+func SetChannelNotifications(channelID string, setting string) error {
+	switch setting {
+	case NotifyMute:
+		return SetChannelMuted(channelID, true)
+	case NotifyUnmute:
+		return SetChannelMuted(channelID, false)
+	}
+
+	level, ok := notifyLevels[setting]
+	if !ok {
+		return fmt.Errorf("invalid setting %q, expected one of %s", setting, strings.Join(NotifySettings, ", "))
+	}
+
+	for _, name := range []string{"desktop", "mobile"} {
+		if err := setChannelNotificationPref(channelID, name, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Whether a conversation already has a notification setting
+func notificationApplied(prefs ChannelNotificationPrefs, setting string) bool {
+	switch setting {
+	case NotifyMute:
+		return prefs.Muted
+	case NotifyUnmute:
+		return !prefs.Muted
+	}
+	return prefs.Desktop == notifyLevels[setting] && prefs.Mobile == notifyLevels[setting]
+}
+
+// Work out which channels need their notifications changed by the policies. Like cleanup
+// policies the first matching policy applies to a channel, and channels that already have
+// the setting of their policy are skipped
+func PlanNotificationPolicies(policies []config.NotificationPolicy) ([]NotifyChange, error) {
+	rules := make([]*Rule, len(policies))
+	for i, p := range policies {
+		rule, err := compilePolicy(p.Name, p.Setting, NotifySettings, p.Match)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+
+	channels, err := GetAllConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := GetChannelNotificationPrefs()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := NewRuleContext()
+	changes := []NotifyChange{}
+	for _, c := range channels {
+		if c.IsArchived {
+			continue
+		}
+
+		i, err := firstMatchingRule(ctx, rules, c)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || notificationApplied(prefs[c.ID], policies[i].Setting) {
+			continue
+		}
+
+		logrus.WithField("channel", c.Name).WithField("policy", policies[i].Name).Debug("notification policy matched")
+		changes = append(changes, NotifyChange{Policy: policies[i].Name, Setting: policies[i].Setting, ChannelID: c.ID, ChannelName: c.Name})
+	}
+
+	slices.SortFunc(changes, func(a, b NotifyChange) int {
+		return strings.Compare(a.ChannelName, b.ChannelName)
+	})
+
+	return changes, nil
+}
+
+// Apply planned notification changes. Failed changes are returned with their error and the rest are still applied
+func ApplyNotificationChanges(changes []NotifyChange) []NotifyChange {
+	applied := make([]NotifyChange, 0, len(changes))
+	for _, change := range changes {
+		if err := SetChannelNotifications(change.ChannelID, change.Setting); err != nil {
+			logrus.WithError(err).WithField("channel", change.ChannelName).Warnf("could not %s channel", change.Setting)
+			change.Error = err.Error()
+		}
+		applied = append(applied, change)
+	}
+	return applied
+}
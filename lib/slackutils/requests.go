@@ -3,16 +3,37 @@ package slackutils
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/graytonio/slack-cli/lib/config"
 	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
 )
 
+// Most times a single request is retried while slack keeps rate limiting it
+const rateLimitRetries = 5
+
+// Run a slack api call, waiting for the time slack asks for and trying again while it is rate limited
+func retryRateLimited(call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+
+		var limited *slack.RateLimitedError
+		if !errors.As(err, &limited) || attempt == rateLimitRetries {
+			return err
+		}
+
+		logrus.WithField("retry_after", limited.RetryAfter).Warn("rate limited by slack, waiting")
+		time.Sleep(limited.RetryAfter)
+	}
+}
+
 func RawSlackRequestFormData(method string, path string, body map[string]string) ([]byte, int, error) {
 	reqUrl, err := url.JoinPath("https://slack.com/api/", path)
 	if err != nil {
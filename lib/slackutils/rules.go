@@ -0,0 +1,435 @@
+package slackutils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+var channelTypes = []string{"public", "private", "dm", "mpdm", "shared"}
+
+var ErrEmptyRule = errors.New("smart section has no rules, set re or match")
+var ErrEmptyPolicy = errors.New("policy has no match rules")
+
+// Most messages read by the workspace wide search of a mentioned_within or posted_within rule.
+// When more messages match each channel is searched on its own instead
+const ruleSearchLimit = 1000
+
+// A compiled SectionRule with its expressions and durations parsed
+type Rule struct {
+	name            *regexp.Regexp
+	topic           *regexp.Regexp
+	purpose         *regexp.Regexp
+	types           []string
+	archived        *bool
+	minMembers      int
+	maxMembers      int
+	activeWithin    time.Duration
+	inactiveFor     time.Duration
+	mentionedWithin time.Duration
+	postedWithin    time.Duration
+	all             []*Rule
+	any             []*Rule
+	not             *Rule
+}
+
+// Compile the rules of a smart section. The re shorthand matches the channel name and
+// is combined with match when both are set
+func CompileSmartSection(smartSection config.SmartSection) (*Rule, error) {
+	rule := config.SectionRule{}
+	switch {
+	case smartSection.ReExpression != "" && smartSection.Match != nil:
+		rule.All = []config.SectionRule{{Name: smartSection.ReExpression}, *smartSection.Match}
+	case smartSection.ReExpression != "":
+		rule.Name = smartSection.ReExpression
+	case smartSection.Match != nil:
+		rule = *smartSection.Match
+	default:
+		return nil, fmt.Errorf("%s: %w", smartSection.SectionName, ErrEmptyRule)
+	}
+
+	compiled, err := CompileRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", smartSection.SectionName, err)
+	}
+	return compiled, nil
+}
+
+// Parse the expressions and durations of a rule and its nested rules
+func CompileRule(rule config.SectionRule) (*Rule, error) {
+	compiled := &Rule{
+		archived:   rule.Archived,
+		minMembers: rule.MinMembers,
+		maxMembers: rule.MaxMembers,
+	}
+
+	var err error
+	for _, re := range []struct {
+		expression string
+		target     **regexp.Regexp
+	}{
+		{rule.Name, &compiled.name},
+		{rule.Topic, &compiled.topic},
+		{rule.Purpose, &compiled.purpose},
+	} {
+		if re.expression == "" {
+			continue
+		}
+		if *re.target, err = regexp.Compile(re.expression); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range []struct {
+		duration string
+		target   *time.Duration
+	}{
+		{rule.ActiveWithin, &compiled.activeWithin},
+		{rule.InactiveFor, &compiled.inactiveFor},
+		{rule.MentionedWithin, &compiled.mentionedWithin},
+		{rule.PostedWithin, &compiled.postedWithin},
+	} {
+		if d.duration == "" {
+			continue
+		}
+		if *d.target, err = ParseDuration(d.duration); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range rule.Type {
+		t = strings.ToLower(t)
+		if !slices.Contains(channelTypes, t) {
+			return nil, fmt.Errorf("invalid channel type %q, expected one of %s", t, strings.Join(channelTypes, ", "))
+		}
+		compiled.types = append(compiled.types, t)
+	}
+
+	for _, r := range rule.All {
+		c, err := CompileRule(r)
+		if err != nil {
+			return nil, err
+		}
+		compiled.all = append(compiled.all, c)
+	}
+
+	for _, r := range rule.Any {
+		c, err := CompileRule(r)
+		if err != nil {
+			return nil, err
+		}
+		compiled.any = append(compiled.any, c)
+	}
+
+	if rule.Not != nil {
+		if compiled.not, err = CompileRule(*rule.Not); err != nil {
+			return nil, err
+		}
+	}
+
+	return compiled, nil
+}
+
+// Check whether a channel meets every condition of the rule. Conditions that only need the
+// channel itself are checked first so api lookups are skipped for channels that can not match
+func (r *Rule) Match(ctx *RuleContext, c slack.Channel) (bool, error) {
+	if r.name != nil && !r.name.MatchString(c.Name) {
+		return false, nil
+	}
+	if r.topic != nil && !r.topic.MatchString(c.Topic.Value) {
+		return false, nil
+	}
+	if r.purpose != nil && !r.purpose.MatchString(c.Purpose.Value) {
+		return false, nil
+	}
+	if r.archived != nil && c.IsArchived != *r.archived {
+		return false, nil
+	}
+	if len(r.types) > 0 && !slices.ContainsFunc(r.types, func(t string) bool { return channelIsType(c, t) }) {
+		return false, nil
+	}
+
+	if r.minMembers > 0 || r.maxMembers > 0 {
+		members, err := ctx.members(c)
+		if err != nil {
+			return false, err
+		}
+		if (r.minMembers > 0 && members < r.minMembers) || (r.maxMembers > 0 && members > r.maxMembers) {
+			return false, nil
+		}
+	}
+
+	if r.activeWithin > 0 || r.inactiveFor > 0 {
+		latest, err := ctx.lastActivity(c)
+		if err != nil {
+			return false, err
+		}
+		if r.activeWithin > 0 && latest.Before(ctx.now.Add(-r.activeWithin)) {
+			return false, nil
+		}
+		if r.inactiveFor > 0 && !latest.Before(ctx.now.Add(-r.inactiveFor)) {
+			return false, nil
+		}
+	}
+
+	if r.mentionedWithin > 0 {
+		mentioned, err := ctx.searchedIn(c, "<@%s>", r.mentionedWithin)
+		if err != nil || !mentioned {
+			return false, err
+		}
+	}
+
+	if r.postedWithin > 0 {
+		posted, err := ctx.searchedIn(c, "from:<@%s>", r.postedWithin)
+		if err != nil || !posted {
+			return false, err
+		}
+	}
+
+	for _, sub := range r.all {
+		ok, err := sub.Match(ctx, c)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	if len(r.any) > 0 {
+		matched := false
+		for _, sub := range r.any {
+			ok, err := sub.Match(ctx, c)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	if r.not != nil {
+		ok, err := r.not.Match(ctx, c)
+		if err != nil || ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Compile the match rule of a cleanup or notification policy after checking its action is one of valid.
+// Unlike smart sections an empty rule is an error as it would match every channel
+func compilePolicy(name string, action string, valid []string, match config.SectionRule) (*Rule, error) {
+	if !slices.Contains(valid, action) {
+		return nil, fmt.Errorf("%s: %q is not one of %s", name, action, strings.Join(valid, ", "))
+	}
+	if reflect.ValueOf(match).IsZero() {
+		return nil, fmt.Errorf("%s: %w", name, ErrEmptyPolicy)
+	}
+
+	rule, err := CompileRule(match)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return rule, nil
+}
+
+// Index of the first rule matching a channel, or -1 when none match
+func firstMatchingRule(ctx *RuleContext, rules []*Rule, c slack.Channel) (int, error) {
+	for i, r := range rules {
+		ok, err := r.Match(ctx, c)
+		if err != nil {
+			return -1, err
+		}
+		if ok {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+func channelIsType(c slack.Channel, t string) bool {
+	switch t {
+	case "dm":
+		return c.IsIM
+	case "mpdm":
+		return c.IsMpIM
+	case "shared":
+		return c.IsShared || c.IsExtShared || c.IsOrgShared
+	case "private":
+		return !c.IsIM && !c.IsMpIM && (c.IsPrivate || c.IsGroup)
+	case "public":
+		return !c.IsIM && !c.IsMpIM && !c.IsPrivate && !c.IsGroup
+	}
+	return false
+}
+
+// RuleContext holds data fetched while evaluating rules so every lookup is made at most once per run
+type RuleContext struct {
+	now time.Time
+
+	mu        sync.Mutex
+	counts    map[string]ConversationCount
+	memberMap map[string]int
+	latestMap map[string]time.Time
+	searchMap map[string]map[string]bool
+	// Per channel search results, for searches with too many matches to read workspace wide
+	channelSearchMap map[string]map[string]bool
+}
+
+func NewRuleContext() *RuleContext {
+	return &RuleContext{
+		now:       time.Now(),
+		memberMap: make(map[string]int),
+		latestMap: make(map[string]time.Time),
+		searchMap: make(map[string]map[string]bool),
+
+		channelSearchMap: make(map[string]map[string]bool),
+	}
+}
+
+func (ctx *RuleContext) members(c slack.Channel) (int, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if n, ok := ctx.memberMap[c.ID]; ok {
+		return n, nil
+	}
+
+	n := c.NumMembers
+	if n == 0 {
+		logrus.WithField("channel", c.Name).Debug("fetching member count")
+		info, err := config.SlackClient.GetConversationInfo(&slack.GetConversationInfoInput{
+			ChannelID:         c.ID,
+			IncludeNumMembers: true,
+		})
+		if err != nil {
+			return 0, err
+		}
+		n = info.NumMembers
+	}
+
+	ctx.memberMap[c.ID] = n
+	return n, nil
+}
+
+// Time of the latest message in a channel, or the zero time for channels without messages
+func (ctx *RuleContext) lastActivity(c slack.Channel) (time.Time, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if t, ok := ctx.latestMap[c.ID]; ok {
+		return t, nil
+	}
+
+	if ctx.counts == nil {
+		logrus.Debug("fetching conversation counts")
+		counts, err := GetClientCounts()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		ctx.counts = make(map[string]ConversationCount)
+		for _, list := range [][]ConversationCount{counts.Channels, counts.MPIMs, counts.IMs} {
+			for _, count := range list {
+				ctx.counts[count.ID] = count
+			}
+		}
+	}
+
+	latest := ""
+	if count, ok := ctx.counts[c.ID]; ok && count.Latest != "" {
+		latest = count.Latest
+	} else {
+		logrus.WithField("channel", c.Name).Debug("fetching latest message")
+		messages, err := GetRecentMessages(c.ID, 1)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if len(messages) > 0 {
+			latest = messages[0].Timestamp
+		}
+	}
+
+	t := time.Time{}
+	if latest != "" {
+		t = ParseTimestamp(latest)
+	}
+
+	ctx.latestMap[c.ID] = t
+	return t, nil
+}
+
+// Whether a channel has messages matching a search within a duration. The query is a format
+// string which gets the id of the authenticated user. The search is first run once across the
+// workspace, when that matches more than ruleSearchLimit messages the result would be cut off
+// so every channel is searched on its own instead
+func (ctx *RuleContext) searchedIn(c slack.Channel, query string, within time.Duration) (bool, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	key := query + within.String()
+	if found, ok := ctx.searchMap[key]; ok {
+		return found[c.ID], nil
+	}
+
+	userID, err := GetCurrentUserID()
+	if err != nil {
+		return false, err
+	}
+	query = fmt.Sprintf(query, userID)
+	since := ctx.now.Add(-within)
+
+	channels, ok := ctx.channelSearchMap[key]
+	if !ok {
+		logrus.WithField("query", query).WithField("within", within).Debug("searching for channels")
+		// Fetch one more than the limit to tell a complete result from a cut off one
+		messages, err := SearchMessagesSince(query, since, ruleSearchLimit+1)
+		if err != nil {
+			return false, err
+		}
+
+		if len(messages) <= ruleSearchLimit {
+			found := make(map[string]bool)
+			for _, m := range messages {
+				found[m.Channel.ID] = true
+			}
+			ctx.searchMap[key] = found
+			return found[c.ID], nil
+		}
+
+		logrus.WithField("query", query).WithField("within", within).Debug("too many matches, searching each channel")
+		channels = make(map[string]bool)
+		ctx.channelSearchMap[key] = channels
+	}
+
+	if found, ok := channels[c.ID]; ok {
+		return found, nil
+	}
+
+	in := "<#" + c.ID + ">"
+	if c.IsIM {
+		in = "<@" + c.User + ">"
+	}
+
+	logrus.WithField("channel", c.Name).WithField("query", query).Debug("searching channel")
+	messages, err := SearchMessagesSince(query+" in:"+in, since, 1)
+	if err != nil {
+		return false, err
+	}
+
+	channels[c.ID] = len(messages) > 0
+	return channels[c.ID], nil
+}
@@ -0,0 +1,81 @@
+package slackutils
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/slack-go/slack"
+)
+
+type ScheduleMessageResponse struct {
+	OK                 bool   `json:"ok"`
+	Error              string `json:"error"`
+	Channel            string `json:"channel"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+	PostAt             int64  `json:"post_at"`
+}
+
+// Schedule a message to be posted to a channel at a later time
+func ScheduleMessage(channelID string, postAt time.Time, text string, threadTS string, blocks []slack.Block) (*ScheduleMessageResponse, error) {
+	payload := map[string]string{
+		"channel": channelID,
+		"post_at": strconv.FormatInt(postAt.Unix(), 10),
+		"text":    text,
+	}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+	if len(blocks) > 0 {
+		blocksEncoded, err := json.Marshal(blocks)
+		if err != nil {
+			return nil, err
+		}
+		payload["blocks"] = string(blocksEncoded)
+	}
+
+	body, _, err := RawSlackRequestFormData("POST", "chat.scheduleMessage", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ScheduleMessageResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.OK {
+		return nil, errors.New(response.Error)
+	}
+
+	return &response, nil
+}
+
+// List every pending scheduled message. An empty channel id lists scheduled messages in all channels
+func GetAllScheduledMessages(channelID string) ([]slack.ScheduledMessage, error) {
+	messages := []slack.ScheduledMessage{}
+	cursor := ""
+
+	for {
+		page, next, err := config.SlackClient.GetScheduledMessages(&slack.GetScheduledMessagesParameters{
+			Channel: channelID,
+			Cursor:  cursor,
+			Limit:   100,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, page...)
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,130 @@
+package slackutils
+
+import (
+	"strings"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// Filters narrowing a search. Each one is turned into a Slack search modifier
+type SearchFilters struct {
+	In     []string
+	From   []string
+	After  string
+	Before string
+	On     string
+}
+
+// Build a Slack search query by appending modifiers for the given filters to the query
+func BuildSearchQuery(query string, filters SearchFilters) string {
+	terms := []string{}
+	if query != "" {
+		terms = append(terms, query)
+	}
+
+	for _, in := range filters.In {
+		terms = append(terms, "in:"+searchChannelModifier(in))
+	}
+
+	for _, from := range filters.From {
+		terms = append(terms, "from:"+searchUserModifier(from))
+	}
+
+	if filters.After != "" {
+		terms = append(terms, "after:"+filters.After)
+	}
+	if filters.Before != "" {
+		terms = append(terms, "before:"+filters.Before)
+	}
+	if filters.On != "" {
+		terms = append(terms, "on:"+filters.On)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+func searchChannelModifier(channel string) string {
+	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+		return channel
+	}
+
+	// Bare ids need to be wrapped so slack does not treat them as a name
+	if channelIDRe.MatchString(channel) {
+		return "<#" + channel + ">"
+	}
+
+	return "#" + channel
+}
+
+func searchUserModifier(user string) string {
+	name := strings.TrimPrefix(user, "@")
+	if userIDRe.MatchString(name) {
+		return "<@" + name + ">"
+	}
+
+	u, err := GetUserByName(name)
+	if err != nil {
+		logrus.WithError(err).WithField("user", name).Debug("could not resolve user for search, using name")
+		return "@" + name
+	}
+
+	return "<@" + u.ID + ">"
+}
+
+// Page through search.messages until limit results have been collected or results run out
+func SearchMessages(query string, sort string, limit int) ([]slack.SearchMessage, error) {
+	matches := []slack.SearchMessage{}
+	params := slack.NewSearchParameters()
+	params.Sort = sort
+	params.Count = min(limit, 100)
+
+	for len(matches) < limit {
+		resp, err := config.SlackClient.SearchMessages(query, params)
+		if err != nil {
+			return nil, err
+		}
+
+		logrus.WithField("page", resp.Paging.Page).WithField("pages", resp.Paging.Pages).Debug("fetched search page")
+		matches = append(matches, resp.Matches...)
+
+		if len(resp.Matches) == 0 || resp.Paging.Page >= resp.Paging.Pages {
+			break
+		}
+		params.Page = resp.Paging.Page + 1
+	}
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Page through search.files until limit results have been collected or results run out
+func SearchFiles(query string, sort string, limit int) ([]slack.File, error) {
+	matches := []slack.File{}
+	params := slack.NewSearchParameters()
+	params.Sort = sort
+	params.Count = min(limit, 100)
+
+	for len(matches) < limit {
+		resp, err := config.SlackClient.SearchFiles(query, params)
+		if err != nil {
+			return nil, err
+		}
+
+		logrus.WithField("page", resp.Paging.Page).WithField("pages", resp.Paging.Pages).Debug("fetched search page")
+		matches = append(matches, resp.Matches...)
+
+		if len(resp.Matches) == 0 || resp.Paging.Page >= resp.Paging.Pages {
+			break
+		}
+		params.Page = resp.Paging.Page + 1
+	}
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
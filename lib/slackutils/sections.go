@@ -3,9 +3,11 @@ package slackutils
 import (
 	"encoding/json"
 	"errors"
-	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/graytonio/slack-cli/lib/config"
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 )
@@ -17,9 +19,10 @@ var (
 type ChannelSection struct {
 	ID                   string `json:"channel_section_id"`
 	Name                 string `json:"name"`
-	Type                 string `json:"standard"`
+	Type                 string `json:"type"`
 	Emoji                string `json:"emoji"`
 	NextChannelSectionID string `json:"next_channel_section_id"`
+	IsExpanded           bool   `json:"is_expanded"`
 	ChannelIdsPage       struct {
 		ChannelIDs []string `json:"channel_ids"`
 	} `json:"channel_ids_page"`
@@ -48,142 +51,265 @@ func GetChannelSections() ([]ChannelSection, error) {
 	return rawBody.ChannelSections, nil
 }
 
-// Create a new channel section
-func CreateSection(name string, emoji string) (string, error) {
-	body, _, err := RawSlackRequestFormData("POST", "users.channelSections.create", map[string]string{
-		"name":  name,
-		"emoji": emoji,
-	})
+type sectionResponse struct {
+	OK               bool   `json:"ok"`
+	Error            string `json:"error"`
+	ChannelSectionID string `json:"channel_section_id"`
+}
+
+func sendSectionRequest(path string, payload map[string]string) (string, error) {
+	body, _, err := RawSlackRequestFormData("POST", path, payload)
 	if err != nil {
 		return "", err
 	}
 
-	return string(body), nil
+	response := sectionResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", err
+	}
+
+	if !response.OK {
+		return "", errors.New(response.Error)
+	}
+
+	return response.ChannelSectionID, nil
 }
 
-type GetSectionResponse struct {
-	OK      bool           `json:"ok"`
-	Section ChannelSection `json:"channel_section"`
+// Create a new channel section and return its id. The section is placed above
+// the section with id nextSectionID, or in the default position when it is empty
+func CreateSection(name string, emoji string, nextSectionID string) (string, error) {
+	payload := map[string]string{
+		"name":  name,
+		"emoji": strings.Trim(emoji, ":"),
+	}
+	if nextSectionID != "" {
+		payload["next_channel_section_id"] = nextSectionID
+	}
+
+	return sendSectionRequest("users.channelSections.create", payload)
 }
 
-// Get a list of channels in a section
-func GetSectionChannels(sectionID string) ([]string, error) {
-	body, _, err := RawSlackRequestJSON("GET", "users.channelSections.get", nil, map[string]string{
+// Update the name, emoji or position of a section. Empty values are left unchanged
+func UpdateSection(sectionID string, name string, emoji string, nextSectionID string) error {
+	payload := map[string]string{
 		"channel_section_id": sectionID,
-	})
-	if err != nil {
-		return nil, err
 	}
-
-	response := GetSectionResponse{}
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, err
+	if name != "" {
+		payload["name"] = name
+	}
+	if emoji != "" {
+		payload["emoji"] = strings.Trim(emoji, ":")
+	}
+	if nextSectionID != "" {
+		payload["next_channel_section_id"] = nextSectionID
 	}
 
-	return response.Section.ChannelIdsPage.ChannelIDs, nil
+	_, err := sendSectionRequest("users.channelSections.update", payload)
+	return err
 }
 
-type moveChannelPayload struct {
-	ChannelSectionID string   `json:"channel_section_id"`
-	ChannelIDs       []string `json:"channel_ids"`
+// Delete a section. Its channels go back to the default Channels section
+func DeleteSection(sectionID string) error {
+	_, err := sendSectionRequest("users.channelSections.delete", map[string]string{
+		"channel_section_id": sectionID,
+	})
+	return err
 }
 
-// Move a channel from one section to another
-func MoveChannelToSection(channelName string, toSectionName string) error {
-	fromSection, err := GetSectionOfChannelName(channelName)
-	if err != nil && !errors.Is(err, ErrChannelSectionNotFound) {
-		return err
-	}
+// Collapse or expand a section in the sidebar
+func SetSectionExpanded(sectionID string, expanded bool) error {
+	_, err := sendSectionRequest("users.channelSections.update", map[string]string{
+		"channel_section_id": sectionID,
+		"is_expanded":        strconv.FormatBool(expanded),
+	})
+	return err
+}
 
-	toSection, err := GetSectionByName(toSectionName)
+// Move every channel in a section back to the default Channels section. Channel names are
+// looked up so the moves can be reported, channels missing from the list keep their id
+func EmptySection(sections []ChannelSection, section ChannelSection) (*MovePlan, error) {
+	channels, err := GetAllConversations()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	channel, err := GetChannelByName(channelName)
-	if err != nil {
-		return err
+	names := make(map[string]string, len(channels))
+	for _, c := range channels {
+		names[c.ID] = c.Name
 	}
 
-	insert := []moveChannelPayload{
-		{
-			ChannelSectionID: toSection.ID,
-			ChannelIDs:       []string{channel.ID},
-		},
+	plan := &MovePlan{
+		Section: section.Name,
+		Moves:   []ChannelMove{},
+		insert:  make(map[string][]string),
+		remove:  make(map[string][]string),
 	}
 
-	insertEncoded, err := json.Marshal(insert)
-	if err != nil {
-		return err
+	target := ""
+	for _, s := range sections {
+		if s.Type == "channels" {
+			target = s.ID
+		}
 	}
 
-	logrus.WithField("action", "insert").WithField("section", toSectionName).Debugf("%s", insertEncoded)
-	payload := map[string]string{
-		"insert": string(insertEncoded),
+	for _, id := range section.ChannelIdsPage.ChannelIDs {
+		plan.remove[section.ID] = append(plan.remove[section.ID], id)
+		if target != "" {
+			plan.insert[target] = append(plan.insert[target], id)
+		}
+		name, ok := names[id]
+		if !ok {
+			name = id
+		}
+
+		plan.Moves = append(plan.Moves, ChannelMove{
+			ChannelID:     id,
+			ChannelName:   name,
+			FromSectionID: section.ID,
+			FromSection:   section.Name,
+			ToSectionID:   target,
+			ToSection:     "channels",
+		})
+	}
+
+	return plan, ApplyMovePlan(plan)
+}
+
+// Order sections the way they appear in the sidebar by following their next section ids
+func OrderSections(sections []ChannelSection) []ChannelSection {
+	byID := map[string]ChannelSection{}
+	isNext := map[string]bool{}
+	for _, s := range sections {
+		byID[s.ID] = s
+		isNext[s.NextChannelSectionID] = true
 	}
 
-	if fromSection != nil {
-		remove := []moveChannelPayload{
-			{
-				ChannelSectionID: fromSection.ID,
-				ChannelIDs:       []string{channel.ID},
-			},
+	ordered := make([]ChannelSection, 0, len(sections))
+	seen := map[string]bool{}
+	for _, head := range sections {
+		if isNext[head.ID] {
+			continue
+		}
+
+		for s, ok := head, true; ok && !seen[s.ID]; s, ok = byID[s.NextChannelSectionID] {
+			seen[s.ID] = true
+			ordered = append(ordered, s)
 		}
+	}
 
-		removeEncoded, err := json.Marshal(remove)
+	// Anything left is part of a cycle, keep the api order for it
+	for _, s := range sections {
+		if !seen[s.ID] {
+			ordered = append(ordered, s)
+		}
+	}
+
+	return ordered
+}
+
+// Get the section named in a smart section config, creating it when it does not exist and
+// updating its emoji and position when they differ from the config. Existing sections are
+// looked up first so running the same smart section again never creates duplicates
+func EnsureSection(sections []ChannelSection, smartSection config.SmartSection) (*ChannelSection, error) {
+	emoji := strings.Trim(smartSection.Emoji, ":")
+
+	nextSectionID := ""
+	if smartSection.Before != "" {
+		next := localGetSectionByName(sections, smartSection.Before)
+		if next == nil {
+			logrus.WithField("section", smartSection.Before).Warn("section to place before not found, keeping default position")
+		} else {
+			nextSectionID = next.ID
+		}
+	}
+
+	section := localGetSectionByName(sections, smartSection.SectionName)
+	if section == nil {
+		logrus.WithField("section", smartSection.SectionName).Debug("creating section")
+		id, err := CreateSection(smartSection.SectionName, emoji, nextSectionID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		logrus.WithField("action", "remove").WithField("section", fromSection.Name).Debugf("%s", removeEncoded)
-		payload["remove"] = string(removeEncoded)
+		return &ChannelSection{ID: id, Name: smartSection.SectionName, Emoji: emoji, NextChannelSectionID: nextSectionID}, nil
 	}
 
-	body, code, err := RawSlackRequestFormData("POST", "users.channelSections.channels.bulkUpdate", payload)
+	emojiChanged := emoji != "" && section.Emoji != emoji
+	positionChanged := nextSectionID != "" && section.NextChannelSectionID != nextSectionID
+	if emojiChanged || positionChanged {
+		logrus.WithField("section", section.Name).Debug("updating section emoji and position")
+		if err := UpdateSection(section.ID, "", emoji, nextSectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return section, nil
+}
+
+type GetSectionResponse struct {
+	OK      bool           `json:"ok"`
+	Section ChannelSection `json:"channel_section"`
+}
+
+// Get a list of channels in a section
+func GetSectionChannels(sectionID string) ([]string, error) {
+	body, _, err := RawSlackRequestJSON("GET", "users.channelSections.get", nil, map[string]string{
+		"channel_section_id": sectionID,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if code != 200 {
-		return errors.New(string(body))
+	response := GetSectionResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return response.Section.ChannelIdsPage.ChannelIDs, nil
+}
+
+type moveChannelPayload struct {
+	ChannelSectionID string   `json:"channel_section_id"`
+	ChannelIDs       []string `json:"channel_ids"`
 }
 
-func ExecuteSmartSection(sectionName string, re string) error {
-	_, err := CreateSection(sectionName, "")
+// Move a channel from one section to another
+func MoveChannelToSection(channelName string, toSectionName string) error {
+	_, err := MoveChannelsToSection([]string{channelName}, toSectionName, false)
+	return err
+}
+
+// Move every channel matching the given names, glob patterns or ids into a section with a single
+// bulkUpdate. Channels and sections are resolved from one snapshot. With dryRun nothing is moved
+func MoveChannelsToSection(patterns []string, toSectionName string, dryRun bool) (*MovePlan, error) {
+	sections, err := GetChannelSections()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	exp, err := regexp.Compile(re)
-	if err != nil {
-		return err
+	toSection := localGetSectionByName(sections, toSectionName)
+	if toSection == nil {
+		return nil, ErrSectionNotFound
 	}
 
 	channels, err := GetAllConversations()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	logrus.WithField("expression", re).Debug("checking for any channels matching regex")
-	channelsToMove := []slack.Channel{}
-	for _, c := range channels {
-		if exp.Match([]byte(c.Name)) {
-			logrus.WithField("channel", c.Name).Debug("matched channel")
-			channelsToMove = append(channelsToMove, c)
-		}
+	matched, err := MatchChannels(channels, patterns)
+	if err != nil {
+		return nil, err
 	}
 
-	logrus.Debug("getting destination section details")
-	section, err := GetSectionByName(sectionName)
-	if err != nil {
-		return err
+	plan := PlanChannelMove(sections, matched, toSection.ID, toSection.Name)
+	if dryRun {
+		return plan, nil
 	}
 
-	return BulkChannelMove(channelsToMove, section.ID)
+	return plan, ApplyMovePlan(plan)
 }
 
 func BulkChannelMove(channels []slack.Channel, sectionID string) error {
@@ -193,18 +319,49 @@ func BulkChannelMove(channels []slack.Channel, sectionID string) error {
 		return err
 	}
 
-	actionData := map[string]map[string][]string{
-		"remove": make(map[string][]string),
-		"insert": make(map[string][]string),
+	sectionName := sectionID
+	for _, s := range sections {
+		if s.ID == sectionID {
+			sectionName = s.Name
+		}
+	}
+
+	return ApplyMovePlan(PlanChannelMove(sections, channels, sectionID, sectionName))
+}
+
+// A channel changing sidebar section
+type ChannelMove struct {
+	ChannelID     string `json:"channel_id"`
+	ChannelName   string `json:"channel"`
+	FromSectionID string `json:"from_section_id,omitempty"`
+	FromSection   string `json:"from_section"`
+	ToSectionID   string `json:"to_section_id"`
+	ToSection     string `json:"to_section"`
+}
+
+// The changes needed to move channels between sections, grouped by section the way bulkUpdate expects them
+type MovePlan struct {
+	Section       string        `json:"section"`
+	CreateSection bool          `json:"create_section,omitempty"`
+	Moves         []ChannelMove `json:"moves"`
+
+	insert map[string][]string
+	remove map[string][]string
+}
+
+// Work out which channels need to be removed from their current section and inserted into the destination
+func PlanChannelMove(sections []ChannelSection, channels []slack.Channel, sectionID string, sectionName string) *MovePlan {
+	plan := &MovePlan{
+		Section: sectionName,
+		Moves:   []ChannelMove{},
+		insert:  make(map[string][]string),
+		remove:  make(map[string][]string),
 	}
 
 	// Build Required Actions
 	for _, c := range channels {
 		// Get where channel is currently
-		fromSection, err := localGetChannelSection(sections, c)
-		if err != nil && !errors.Is(err, ErrChannelSectionNotFound) {
-			return err
-		}
+		fromSection, _ := localGetChannelSection(sections, c)
 
 		current_name := "channels"
 		if fromSection != nil {
@@ -219,33 +376,51 @@ func BulkChannelMove(channels []slack.Channel, sectionID string) error {
 			continue
 		}
 
+		move := ChannelMove{
+			ChannelID:   c.ID,
+			ChannelName: c.Name,
+			FromSection: current_name,
+			ToSectionID: sectionID,
+			ToSection:   sectionName,
+		}
+
 		// Add the channel to the right section
 		logrus.WithField("channel", c.Name).WithField("action", "insert").WithField("section", sectionID).Debug("adding channel to section")
-		actionData["insert"][sectionID] = append(actionData["insert"][sectionID], c.ID)
+		plan.insert[sectionID] = append(plan.insert[sectionID], c.ID)
 
 		// If channel is in another section remove it from there
 		if fromSection != nil {
 			logrus.WithField("channel", c.Name).WithField("action", "remove").WithField("section", fromSection.ID).Debug("removing channel from section")
-			actionData["remove"][fromSection.ID] = append(actionData["remove"][fromSection.ID], c.ID)
+			plan.remove[fromSection.ID] = append(plan.remove[fromSection.ID], c.ID)
+			move.FromSectionID = fromSection.ID
 		}
+
+		plan.Moves = append(plan.Moves, move)
 	}
 
+	return plan
+}
+
+// Send the changes in a plan with a single bulkUpdate request
+func ApplyMovePlan(plan *MovePlan) error {
 	payloadData := map[string][]moveChannelPayload{
-		"remove": reduceActionMap(actionData["remove"]),
-		"insert": reduceActionMap(actionData["insert"]),
+		"remove": reduceActionMap(plan.remove),
+		"insert": reduceActionMap(plan.insert),
 	}
 
-	if payloadData["insert"] == nil {
+	if payloadData["insert"] == nil && payloadData["remove"] == nil {
 		return nil
 	}
 
 	payload := make(map[string]string)
 
-	insertEncoded, err := json.Marshal(payloadData["insert"])
-	if err != nil {
-		return err
+	if payloadData["insert"] != nil {
+		insertEncoded, err := json.Marshal(payloadData["insert"])
+		if err != nil {
+			return err
+		}
+		payload["insert"] = string(insertEncoded)
 	}
-	payload["insert"] = string(insertEncoded)
 
 	if payloadData["remove"] != nil {
 		removeEncoded, err := json.Marshal(payloadData["remove"])
@@ -271,6 +446,32 @@ func BulkChannelMove(channels []slack.Channel, sectionID string) error {
 	return nil
 }
 
+// Combine several plans into one so they can be applied with a single bulkUpdate
+func MergeMovePlans(plans []*MovePlan) *MovePlan {
+	merged := &MovePlan{
+		Moves:  []ChannelMove{},
+		insert: make(map[string][]string),
+		remove: make(map[string][]string),
+	}
+
+	for _, plan := range plans {
+		merged.Moves = append(merged.Moves, plan.Moves...)
+		for sectionID, ids := range plan.insert {
+			merged.insert[sectionID] = append(merged.insert[sectionID], ids...)
+		}
+		for sectionID, ids := range plan.remove {
+			merged.remove[sectionID] = append(merged.remove[sectionID], ids...)
+		}
+	}
+
+	return merged
+}
+
+// Placeholder id for a section that would be created by a plan
+func newSectionID(name string) string {
+	return "new:" + name
+}
+
 func reduceActionMap(action map[string][]string) (payload []moveChannelPayload) {
 	for sectionID, channels := range action {
 		payload = append(payload, moveChannelPayload{ChannelSectionID: sectionID, ChannelIDs: channels})
@@ -286,3 +487,12 @@ func localGetChannelSection(sections []ChannelSection, c slack.Channel) (*Channe
 	}
 	return nil, ErrChannelSectionNotFound
 }
+
+func localGetSectionByName(sections []ChannelSection, name string) *ChannelSection {
+	for _, s := range sections {
+		if s.Name == name {
+			return &s
+		}
+	}
+	return nil
+}
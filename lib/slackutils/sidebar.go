@@ -0,0 +1,202 @@
+package slackutils
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// A portable snapshot of the sidebar. Channels are stored by name so a layout can be applied to another machine or workspace
+type SidebarLayout struct {
+	Sections []LayoutSection `yaml:"sections" json:"sections"`
+}
+
+type LayoutSection struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Type is only set for the built in sections such as channels and direct_messages
+	Type     string   `yaml:"type,omitempty" json:"type,omitempty"`
+	Emoji    string   `yaml:"emoji,omitempty" json:"emoji,omitempty"`
+	Channels []string `yaml:"channels,omitempty" json:"channels,omitempty"`
+}
+
+// The changes needed to make the sidebar match a layout
+type ImportPlan struct {
+	Create  []string      `json:"create"`
+	Update  []string      `json:"update"`
+	Moves   []ChannelMove `json:"moves"`
+	Missing []string      `json:"missing_channels"`
+}
+
+// Snapshot the sections of the sidebar in order with their emoji and channels
+func ExportSidebar() (*SidebarLayout, error) {
+	sections, err := GetChannelSections()
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := GetAllConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, c := range channels {
+		names[c.ID] = c.Name
+	}
+
+	layout := &SidebarLayout{Sections: []LayoutSection{}}
+	for _, s := range OrderSections(sections) {
+		section := LayoutSection{Name: s.Name, Emoji: s.Emoji}
+		if !isCustomSection(s.Type) {
+			section.Type = s.Type
+		}
+
+		for _, id := range s.ChannelIdsPage.ChannelIDs {
+			if name := names[id]; name != "" {
+				section.Channels = append(section.Channels, name)
+			} else {
+				section.Channels = append(section.Channels, id)
+			}
+		}
+
+		layout.Sections = append(layout.Sections, section)
+	}
+
+	return layout, nil
+}
+
+// Make the sidebar match a layout using as few requests as possible. Missing sections are created,
+// sections whose emoji or position differ are updated and all channel moves are sent in one bulkUpdate.
+// Sections and channels not in the layout are left alone. With dryRun nothing is changed
+func ImportSidebar(layout *SidebarLayout, dryRun bool) (*ImportPlan, error) {
+	sections, err := GetChannelSections()
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := GetAllConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{Create: []string{}, Update: []string{}, Moves: []ChannelMove{}, Missing: []string{}}
+
+	// Find or create every section in the layout
+	ids := make([]string, len(layout.Sections))
+	for i, ls := range layout.Sections {
+		section := findLayoutSection(sections, ls)
+		if section != nil {
+			ids[i] = section.ID
+			continue
+		}
+
+		if !isCustomSection(ls.Type) {
+			logrus.WithField("type", ls.Type).Warn("built in section not found, skipping")
+			continue
+		}
+
+		plan.Create = append(plan.Create, ls.Name)
+		id := newSectionID(ls.Name)
+		if !dryRun {
+			id, err = CreateSection(ls.Name, ls.Emoji, "")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ids[i] = id
+		sections = append(sections, ChannelSection{ID: id, Name: ls.Name, Type: "standard", Emoji: ls.Emoji})
+	}
+
+	// Fix emoji and order from the bottom up so every section is placed above one already in position
+	for i := len(layout.Sections) - 1; i >= 0; i-- {
+		if ids[i] == "" {
+			continue
+		}
+		section := findLayoutSection(sections, layout.Sections[i])
+
+		next := ""
+		for j := i + 1; j < len(ids) && next == ""; j++ {
+			next = ids[j]
+		}
+
+		emoji := ""
+		if layout.Sections[i].Emoji != "" && layout.Sections[i].Emoji != section.Emoji {
+			emoji = layout.Sections[i].Emoji
+		}
+		if next == section.NextChannelSectionID {
+			next = ""
+		}
+
+		if emoji == "" && next == "" {
+			continue
+		}
+
+		plan.Update = append(plan.Update, layoutSectionName(layout.Sections[i]))
+		if !dryRun {
+			if err := UpdateSection(section.ID, "", emoji, next); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Assign channels to their sections
+	byName := map[string]slack.Channel{}
+	for _, c := range channels {
+		byName[c.Name] = c
+		byName[c.ID] = c
+	}
+
+	movePlans := []*MovePlan{}
+	for i, ls := range layout.Sections {
+		if ids[i] == "" {
+			continue
+		}
+
+		toMove := []slack.Channel{}
+		for _, name := range ls.Channels {
+			c, ok := byName[name]
+			if !ok {
+				plan.Missing = append(plan.Missing, name)
+				continue
+			}
+			toMove = append(toMove, c)
+		}
+
+		movePlans = append(movePlans, PlanChannelMove(sections, toMove, ids[i], layoutSectionName(ls)))
+	}
+
+	merged := MergeMovePlans(movePlans)
+	plan.Moves = merged.Moves
+
+	if !dryRun {
+		if err := ApplyMovePlan(merged); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Sections created by users have the standard type, everything else is built in
+func isCustomSection(sectionType string) bool {
+	return sectionType == "" || sectionType == "standard"
+}
+
+func findLayoutSection(sections []ChannelSection, ls LayoutSection) *ChannelSection {
+	for _, s := range sections {
+		if isCustomSection(ls.Type) && isCustomSection(s.Type) && s.Name == ls.Name {
+			return &s
+		}
+		if !isCustomSection(ls.Type) && s.Type == ls.Type {
+			return &s
+		}
+	}
+	return nil
+}
+
+func layoutSectionName(ls LayoutSection) string {
+	if ls.Name == "" {
+		return ls.Type
+	}
+	return ls.Name
+}
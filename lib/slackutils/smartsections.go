@@ -0,0 +1,140 @@
+package slackutils
+
+import (
+	"slices"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// A channel matched by more than one smart section
+type SectionConflict struct {
+	ChannelID   string   `json:"channel_id"`
+	ChannelName string   `json:"channel"`
+	Sections    []string `json:"sections"`
+	Winner      string   `json:"winner"`
+}
+
+// The combined result of running several smart sections against one snapshot of the sidebar
+type SortPlan struct {
+	Sections  []*MovePlan       `json:"sections"`
+	Conflicts []SectionConflict `json:"conflicts"`
+}
+
+func ExecuteSmartSection(smartSection config.SmartSection) error {
+	_, err := ExecuteSmartSections([]config.SmartSection{smartSection})
+	return err
+}
+
+// Sort channels into every smart section with a single bulkUpdate. Missing sections are
+// created first. The returned plan describes the moves made and any conflicts
+func ExecuteSmartSections(smartSections []config.SmartSection) (*SortPlan, error) {
+	channels, err := GetAllConversations()
+	if err != nil {
+		return nil, err
+	}
+
+	return ExecuteSmartSectionsOn(smartSections, channels)
+}
+
+// Sort only the given channels into the smart sections, leaving every other channel where it is
+func ExecuteSmartSectionsOn(smartSections []config.SmartSection, channels []slack.Channel) (*SortPlan, error) {
+	for _, s := range smartSections {
+		if _, err := CompileSmartSection(s); err != nil {
+			return nil, err
+		}
+	}
+
+	sections, err := GetChannelSections()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range smartSections {
+		section, err := EnsureSection(sections, s)
+		if err != nil {
+			return nil, err
+		}
+
+		// The destination may have just been created so it is added to the snapshot the plan is built from
+		if localGetSectionByName(sections, section.Name) == nil {
+			sections = append(sections, *section)
+		}
+	}
+
+	plan, err := PlanSmartSections(sections, channels, smartSections, NewRuleContext())
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, ApplyMovePlan(MergeMovePlans(plan.Sections))
+}
+
+// Assign every channel to at most one smart section without changing anything. When several
+// sections match a channel the one with the highest priority wins, ties go to the section
+// listed first. Sections that do not exist yet are planned against a placeholder id
+func PlanSmartSections(sections []ChannelSection, channels []slack.Channel, smartSections []config.SmartSection, ctx *RuleContext) (*SortPlan, error) {
+	rules := make([]*Rule, len(smartSections))
+	for i, s := range smartSections {
+		rule, err := CompileSmartSection(s)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+
+	assigned := make([][]slack.Channel, len(smartSections))
+	conflicts := []SectionConflict{}
+
+	for _, c := range channels {
+		winner := -1
+		matched := []string{}
+		for i, rule := range rules {
+			ok, err := rule.Match(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			logrus.WithField("channel", c.Name).WithField("section", smartSections[i].SectionName).Debug("matched channel")
+			if !slices.Contains(matched, smartSections[i].SectionName) {
+				matched = append(matched, smartSections[i].SectionName)
+			}
+			if winner == -1 || smartSections[i].Priority > smartSections[winner].Priority {
+				winner = i
+			}
+		}
+
+		if winner == -1 {
+			continue
+		}
+
+		assigned[winner] = append(assigned[winner], c)
+		if len(matched) > 1 {
+			conflicts = append(conflicts, SectionConflict{
+				ChannelID:   c.ID,
+				ChannelName: c.Name,
+				Sections:    matched,
+				Winner:      smartSections[winner].SectionName,
+			})
+		}
+	}
+
+	plan := &SortPlan{Sections: []*MovePlan{}, Conflicts: conflicts}
+	for i, s := range smartSections {
+		section := localGetSectionByName(sections, s.SectionName)
+		if section == nil {
+			p := PlanChannelMove(sections, assigned[i], newSectionID(s.SectionName), s.SectionName)
+			p.CreateSection = true
+			plan.Sections = append(plan.Sections, p)
+			continue
+		}
+
+		plan.Sections = append(plan.Sections, PlanChannelMove(sections, assigned[i], section.ID, section.Name))
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,90 @@
+package slackutils
+
+import (
+	"strings"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+)
+
+// The status, presence and do not disturb state of a user
+type UserStatus struct {
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Emoji      string     `json:"emoji,omitempty"`
+	Text       string     `json:"text,omitempty"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+	Presence   string     `json:"presence,omitempty"`
+	DND        bool       `json:"dnd"`
+	DNDEnd     *time.Time `json:"dnd_end,omitempty"`
+}
+
+// Get the custom status, presence and do not disturb state of a user
+func GetUserStatus(userID string) (*UserStatus, error) {
+	user, err := config.SlackClient.GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &UserStatus{
+		UserID: userID,
+		Name:   UserDisplayName(user),
+		Emoji:  user.Profile.StatusEmoji,
+		Text:   user.Profile.StatusText,
+	}
+
+	if user.Profile.StatusExpiration > 0 {
+		expiration := time.Unix(int64(user.Profile.StatusExpiration), 0)
+		status.Expiration = &expiration
+	}
+
+	presence, err := config.SlackClient.GetUserPresence(userID)
+	if err != nil {
+		logrus.WithError(err).WithField("user", userID).Debug("could not get presence")
+	} else {
+		status.Presence = presence.Presence
+	}
+
+	dnd, err := config.SlackClient.GetDNDInfo(&userID)
+	if err != nil {
+		logrus.WithError(err).WithField("user", userID).Debug("could not get dnd info")
+		return status, nil
+	}
+
+	now := time.Now()
+	switch {
+	case dnd.SnoozeEnabled && dnd.SnoozeEndTime > 0:
+		end := time.Unix(int64(dnd.SnoozeEndTime), 0)
+		status.DND = end.After(now)
+		status.DNDEnd = &end
+	case dnd.Enabled && dnd.NextStartTimestamp > 0:
+		start := time.Unix(int64(dnd.NextStartTimestamp), 0)
+		end := time.Unix(int64(dnd.NextEndTimestamp), 0)
+		status.DND = !start.After(now) && end.After(now)
+		if status.DND {
+			status.DNDEnd = &end
+		}
+	}
+
+	return status, nil
+}
+
+// Set the custom status of the authenticated user. A zero expiration keeps the status until it is cleared
+func SetStatus(emoji string, text string, expiration time.Time) error {
+	var expires int64
+	if !expiration.IsZero() {
+		expires = expiration.Unix()
+	}
+
+	return config.SlackClient.SetUserCustomStatus(text, NormalizeEmoji(emoji), expires)
+}
+
+// Wrap an emoji name in colons as expected by the api, e.g. "palm_tree" becomes ":palm_tree:"
+func NormalizeEmoji(name string) string {
+	name = strings.Trim(strings.TrimSpace(name), ":")
+	if name == "" {
+		return ""
+	}
+	return ":" + name + ":"
+}
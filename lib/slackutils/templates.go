@@ -0,0 +1,168 @@
+package slackutils
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+)
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+var templateFuncs = template.FuncMap{
+	"env":   os.Getenv,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join":  templateJoin,
+	"now": func(layout string) string {
+		return time.Now().Format(layout)
+	},
+	"default": func(fallback any, value any) any {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// Join the elements of any list with a separator. Lists decoded from json are []any so
+// strings.Join can not be used directly
+func templateJoin(values any, sep string) string {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if values == nil {
+			return ""
+		}
+		return fmt.Sprint(values)
+	}
+
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+// Get a message template from the config by name
+func GetTemplate(name string) (*config.MessageTemplate, error) {
+	t, ok := config.GetConfig().Templates[name]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+	return &t, nil
+}
+
+// Render a configured message template with the given variables. Referencing a variable that
+// was not provided is an error so typos do not produce broken messages, except for variables
+// passed to default which are set to nil so default can replace them
+func RenderTemplate(name string, vars map[string]any) (string, error) {
+	t, err := GetTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return "", err
+	}
+
+	data := maps.Clone(vars)
+	if data == nil {
+		data = map[string]any{}
+	}
+	for _, key := range defaultedVariables(tmpl.Tree.Root) {
+		if _, ok := data[key]; !ok {
+			data[key] = nil
+		}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// Names of the variables given a fallback with default, either piped into it as
+// {{ .name | default "value" }} or passed to it as {{ default "value" .name }}
+func defaultedVariables(node parse.Node) []string {
+	keys := []string{}
+
+	var walk func(parse.Node)
+	walkPipe := func(pipe *parse.PipeNode) {
+		if pipe == nil {
+			return
+		}
+		for i, cmd := range pipe.Cmds {
+			if isDefaultCommand(cmd) {
+				for _, arg := range cmd.Args[min(2, len(cmd.Args)):] {
+					keys = append(keys, variableName(arg)...)
+				}
+				if i > 0 && len(pipe.Cmds[i-1].Args) == 1 {
+					keys = append(keys, variableName(pipe.Cmds[i-1].Args[0])...)
+				}
+			}
+			for _, arg := range cmd.Args {
+				walk(arg)
+			}
+		}
+	}
+
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walkPipe(n.Pipe)
+		case *parse.PipeNode:
+			walkPipe(n)
+		case *parse.TemplateNode:
+			walkPipe(n.Pipe)
+		case *parse.IfNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+
+	walk(node)
+	return keys
+}
+
+func isDefaultCommand(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) == 0 {
+		return false
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	return ok && ident.Ident == "default"
+}
+
+// The top level variable a node reads, such as name for .name
+func variableName(node parse.Node) []string {
+	if field, ok := node.(*parse.FieldNode); ok && len(field.Ident) == 1 {
+		return []string{field.Ident[0]}
+	}
+	return nil
+}
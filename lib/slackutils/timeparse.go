@@ -0,0 +1,164 @@
+package slackutils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+)
+
+var durationRe = regexp.MustCompile(`^(?:(\d+)w)?(?:(\d+)d)?(.*)$`)
+
+var clockLayouts = []string{"15:04", "3pm", "3:04pm", "3 pm", "3:04 pm"}
+
+var ErrInvalidTime = errors.New("could not parse time")
+
+// ParseDuration extends time.ParseDuration with support for day (d) and week (w) units, e.g. "1w2d3h"
+func ParseDuration(s string) (time.Duration, error) {
+	parts := durationRe.FindStringSubmatch(strings.TrimSpace(s))
+	if parts == nil || s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d time.Duration
+	if parts[1] != "" {
+		weeks, _ := strconv.Atoi(parts[1])
+		d += time.Duration(weeks) * 7 * 24 * time.Hour
+	}
+	if parts[2] != "" {
+		days, _ := strconv.Atoi(parts[2])
+		d += time.Duration(days) * 24 * time.Hour
+	}
+	if parts[3] != "" {
+		rest, err := time.ParseDuration(parts[3])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d += rest
+	}
+
+	return d, nil
+}
+
+// ParseTime parses a human friendly time relative to now, in the location of now.
+// Accepted forms are absolute dates ("2024-01-02", "2024-01-02 15:04", RFC3339),
+// a day ("today", "tomorrow", a weekday or a date) optionally followed by a clock
+// time ("9am", "17:30") and a clock time on its own which means the next time the
+// clock reaches it. A day without a clock time defaults to 9am.
+func ParseTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	loc := now.Location()
+
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+
+	input = strings.ToLower(input)
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", input, loc); err == nil {
+		return t, nil
+	}
+
+	day, clock, _ := strings.Cut(input, " ")
+
+	date, ok := parseDay(day, now)
+	if !ok {
+		// No day given so the whole input must be a clock time
+		hour, minute, ok := parseClock(input)
+		if !ok {
+			return time.Time{}, fmt.Errorf("%w %q", ErrInvalidTime, input)
+		}
+
+		t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	hour, minute := 9, 0
+	if clock != "" {
+		hour, minute, ok = parseClock(clock)
+		if !ok {
+			return time.Time{}, fmt.Errorf("%w %q", ErrInvalidTime, input)
+		}
+	}
+
+	t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc)
+
+	// A weekday that already passed today refers to next week
+	if _, isWeekday := weekdays[day]; isWeekday && !t.After(now) {
+		t = t.AddDate(0, 0, 7)
+	}
+
+	return t, nil
+}
+
+// ParseFutureTime accepts either a delay from now such as "2h" or "3d", or any time accepted by ParseTime
+func ParseFutureTime(input string, now time.Time) (time.Time, error) {
+	if d, err := ParseDuration(input); err == nil {
+		return now.Add(d), nil
+	}
+	return ParseTime(input, now)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func parseDay(day string, now time.Time) (time.Time, bool) {
+	switch day {
+	case "today":
+		return now, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	}
+
+	if wd, ok := weekdays[day]; ok {
+		return now.AddDate(0, 0, (int(wd)-int(now.Weekday())+7)%7), true
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", day, now.Location()); err == nil {
+		return t, true
+	}
+
+	return time.Time{}, false
+}
+
+func parseClock(clock string) (int, int, bool) {
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, clock); err == nil {
+			return t.Hour(), t.Minute(), true
+		}
+	}
+	return 0, 0, false
+}
+
+// Get the timezone configured on the authenticated user's slack profile, falling back to the local timezone
+func GetUserLocation() (*time.Location, error) {
+	userID, err := GetCurrentUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := config.SlackClient.GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TZ == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(user.TZ)
+}
@@ -0,0 +1,105 @@
+package slackutils
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/slack-go/slack"
+)
+
+// UserCache provides a thread-safe cache for mapping Slack user IDs to display names.
+type UserCache struct {
+	mu    sync.RWMutex
+	users map[string]string
+}
+
+func NewUserCache() *UserCache {
+	return &UserCache{users: make(map[string]string)}
+}
+
+func (c *UserCache) Get(id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	name, ok := c.users[id]
+	return name, ok
+}
+
+func (c *UserCache) Set(id, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[id] = name
+}
+
+// Resolve returns the display name of a user, looking it up through the api
+// and caching it when it is not already known.
+func (c *UserCache) Resolve(id string) (string, error) {
+	if name, ok := c.Get(id); ok {
+		return name, nil
+	}
+
+	user, err := config.SlackClient.GetUserInfo(id)
+	if err != nil {
+		return "", err
+	}
+
+	name := UserDisplayName(user)
+	c.Set(id, name)
+	return name, nil
+}
+
+// Load fills the cache with every user in the workspace using a single paged users.list
+// so resolving many users does not need a users.info call each
+func (c *UserCache) Load() error {
+	users, err := config.SlackClient.GetUsers()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range users {
+		c.users[users[i].ID] = UserDisplayName(&users[i])
+	}
+	return nil
+}
+
+// UserDisplayName picks the name Slack shows for a user, preferring the
+// display name over the real name and finally the account name.
+func UserDisplayName(user *slack.User) string {
+	name := user.Profile.DisplayName
+	if name == "" {
+		name = user.RealName
+	}
+	if name == "" {
+		name = user.Name
+	}
+	return name
+}
+
+// Search the workspace directory for users whose names, title or email contain the filter, ignoring case.
+// Deleted users and bots are skipped unless requested
+func SearchUsers(filter string, includeDeleted bool, includeBots bool) ([]slack.User, error) {
+	users, err := config.SlackClient.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	filter = strings.ToLower(filter)
+	matches := []slack.User{}
+	for _, u := range users {
+		if (u.Deleted && !includeDeleted) || ((u.IsBot || u.ID == "USLACKBOT") && !includeBots) {
+			continue
+		}
+
+		fields := []string{u.Name, u.RealName, u.Profile.DisplayName, u.Profile.Title, u.Profile.Email}
+		if filter == "" || slices.ContainsFunc(fields, func(field string) bool {
+			return strings.Contains(strings.ToLower(field), filter)
+		}) {
+			matches = append(matches, u)
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,155 @@
+package slackutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/graytonio/slack-cli/lib/config"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// How many moves the watch state keeps so the file does not grow forever
+const watchStateMaxMoves = 1000
+
+// A channel moved by the watcher
+type WatchedMove struct {
+	ChannelMove
+	MovedAt time.Time `json:"moved_at"`
+}
+
+// What the smart section watcher has already done, persisted between runs
+type WatchState struct {
+	LastRun time.Time       `json:"last_run"`
+	Seen    map[string]bool `json:"seen"`
+	Moved   []WatchedMove   `json:"moved"`
+}
+
+// Read the watch state from a file. A missing file is an empty state
+func LoadWatchState(path string) (*WatchState, error) {
+	state := &WatchState{Seen: make(map[string]bool), Moved: []WatchedMove{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Seen == nil {
+		state.Seen = make(map[string]bool)
+	}
+
+	return state, nil
+}
+
+// Write the watch state to a file, creating its directory when needed
+func (s *WatchState) Save(path string) error {
+	if len(s.Moved) > watchStateMaxMoves {
+		s.Moved = s.Moved[len(s.Moved)-watchStateMaxMoves:]
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Run the smart sections against newly joined channels every interval until the context is cancelled.
+// Channels are only considered once so manual moves are never undone. The first run without a
+// state file only records the current channels so only channels joined afterwards are sorted.
+// onRun is called with the plan of every run that moved channels
+func WatchSmartSections(ctx context.Context, smartSections []config.SmartSection, interval time.Duration, statePath string, onRun func(*SortPlan)) error {
+	for _, s := range smartSections {
+		if _, err := CompileSmartSection(s); err != nil {
+			return err
+		}
+	}
+
+	state, err := LoadWatchState(statePath)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := watchRun(smartSections, state, statePath, onRun); err != nil {
+			// Keep watching through temporary api failures
+			logrus.WithError(err).Warn("smart section run failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func watchRun(smartSections []config.SmartSection, state *WatchState, statePath string, onRun func(*SortPlan)) error {
+	channels, err := GetAllConversations()
+	if err != nil {
+		return err
+	}
+
+	if state.LastRun.IsZero() {
+		logrus.WithField("channels", len(channels)).Info("no watch state found, only channels joined from now on will be sorted")
+		for _, c := range channels {
+			state.Seen[c.ID] = true
+		}
+		state.LastRun = time.Now()
+		return state.Save(statePath)
+	}
+
+	newChannels := []slack.Channel{}
+	for _, c := range channels {
+		if !state.Seen[c.ID] {
+			newChannels = append(newChannels, c)
+		}
+	}
+
+	logrus.WithField("new_channels", len(newChannels)).Debug("checking newly joined channels")
+
+	if len(newChannels) > 0 {
+		plan, err := ExecuteSmartSectionsOn(smartSections, newChannels)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		moved := 0
+		for _, section := range plan.Sections {
+			for _, m := range section.Moves {
+				state.Moved = append(state.Moved, WatchedMove{ChannelMove: m, MovedAt: now})
+				moved++
+			}
+		}
+
+		for _, c := range newChannels {
+			state.Seen[c.ID] = true
+		}
+
+		if moved > 0 || len(plan.Conflicts) > 0 {
+			onRun(plan)
+		}
+	}
+
+	state.LastRun = time.Now()
+	return state.Save(statePath)
+}
@@ -0,0 +1,20 @@
+package tui
+
+import "github.com/graytonio/slack-cli/lib/slackutils"
+
+type (
+	EmojiCache = slackutils.EmojiCache
+	UserCache  = slackutils.UserCache
+)
+
+func NewUserCache() *UserCache {
+	return slackutils.NewUserCache()
+}
+
+// NewEmojiCache returns an emoji cache that renders image-only custom emoji
+// with a styled fallback.
+func NewEmojiCache() *EmojiCache {
+	return slackutils.NewEmojiCache(func(name string) string {
+		return customEmojiStyle.Render("[:" + name + ":]")
+	})
+}
@@ -107,15 +107,11 @@ func fetchChannels() tea.Cmd {
 
 func fetchMessages(channelID string) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := config.SlackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
-			ChannelID:          channelID,
-			Limit:              50,
-			IncludeAllMetadata: true,
-		})
+		msgs, err := slackutils.GetRecentMessages(channelID, 50)
 		if err != nil {
 			return MessagesLoadedMsg{ChannelID: channelID, Err: err}
 		}
-		return MessagesLoadedMsg{Messages: resp.Messages, ChannelID: channelID}
+		return MessagesLoadedMsg{Messages: msgs, ChannelID: channelID}
 	}
 }
 
@@ -124,16 +120,11 @@ func pollMessages(channelID, latestTS string) tea.Cmd {
 		if channelID == "" {
 			return NewMessagesMsg{}
 		}
-		resp, err := config.SlackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
-			ChannelID:          channelID,
-			Oldest:             latestTS,
-			Limit:              100,
-			IncludeAllMetadata: true,
-		})
+		msgs, err := slackutils.GetMessagesSince(channelID, latestTS)
 		if err != nil {
 			return NewMessagesMsg{ChannelID: channelID, Err: err}
 		}
-		return NewMessagesMsg{Messages: resp.Messages, ChannelID: channelID}
+		return NewMessagesMsg{Messages: msgs, ChannelID: channelID}
 	}
 }
 
@@ -143,14 +134,7 @@ func resolveUser(userID string) tea.Cmd {
 		if err != nil {
 			return UserResolvedMsg{UserID: userID, Err: err}
 		}
-		name := user.Profile.DisplayName
-		if name == "" {
-			name = user.RealName
-		}
-		if name == "" {
-			name = user.Name
-		}
-		return UserResolvedMsg{UserID: userID, Name: name}
+		return UserResolvedMsg{UserID: userID, Name: slackutils.UserDisplayName(user)}
 	}
 }
 